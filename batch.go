@@ -0,0 +1,172 @@
+package kanaco
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+type (
+	// Batch converts the files of directory trees concurrently.
+	Batch struct {
+		Include []string // patterns of the files to convert, all files when empty
+		Exclude []string // patterns of the files and directories to skip
+		Workers int      // files converted at once, the number of CPUs when 0
+		DryRun  bool     // report the changes without writing the files
+		// Convert converts the content of a file. NewBatch sets it to the
+		// conversion of UTF-8 text with the mode.
+		Convert func([]byte) ([]byte, error)
+	}
+	// FileResult is the result of a file of a Batch.
+	FileResult struct {
+		Path    string
+		Changed bool
+		Skipped bool // binary file
+		Err     error
+	}
+)
+
+// sniffLen is the length of the head of a file sniffed for binary content.
+const sniffLen = 8000
+
+var ErrNotUTF8 = errors.New("kanaco: not UTF-8 text")
+
+func NewBatch(mode string, opts ...Option) *Batch {
+	cv := NewConverter(mode, opts...)
+	return &Batch{Convert: func(b []byte) ([]byte, error) {
+		if !utf8.Valid(b) {
+			return nil, ErrNotUTF8
+		}
+		return cv.Byte(b), nil
+	}}
+}
+
+// Run converts the regular files under roots and returns the result of each
+// file in the order of the paths. Patterns are matched by path.Match against
+// the name and the slash separated path relative to the root, so "*.txt"
+// matches in any directory. A file containing a NUL byte in its head is
+// binary and is skipped. Files are replaced atomically with a temporary file,
+// keeping their permissions.
+func (b *Batch) Run(roots ...string) []FileResult {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	paths := make(chan string)
+	results := []FileResult{}
+	mu := sync.Mutex{}
+	report := func(r FileResult) {
+		mu.Lock()
+		results = append(results, r)
+		mu.Unlock()
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				report(b.convertFile(path))
+			}
+		}()
+	}
+	for _, root := range roots {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				report(FileResult{Path: path, Err: err})
+				return nil
+			}
+			rel, _ := filepath.Rel(root, path)
+			if path != root && match(b.Exclude, d.Name(), rel) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || (len(b.Include) > 0 && !match(b.Include, d.Name(), rel)) {
+				return nil
+			}
+			paths <- path
+			return nil
+		})
+	}
+	close(paths)
+	wg.Wait()
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+func (b *Batch) convertFile(path string) FileResult {
+	r := FileResult{Path: path}
+	in, err := os.ReadFile(path)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	head := in
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		r.Skipped = true
+		return r
+	}
+	out, err := b.Convert(in)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Changed = !bytes.Equal(in, out)
+	if r.Changed && !b.DryRun {
+		r.Err = writeAtomic(path, out)
+	}
+	return r
+}
+
+// writeAtomic replaces the file at path with b through a temporary file in
+// the same directory.
+func writeAtomic(path string, b []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func match(patterns []string, name, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
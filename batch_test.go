@@ -0,0 +1,71 @@
+package kanaco
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":          "ｶﾞｽ",
+		"b.txt":          "ガス",
+		"c.md":           "ｶﾞｽ",
+		"bin.txt":        "ｶﾞｽ\x00",
+		"bad.txt":        "\xff",
+		"sub/d.txt":      "ｱｲｳ",
+		"vendor/e.txt":   "ｱｲｳ",
+		"sub/vendor.txt": "ｱｲｳ",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0600)
+	}
+	b := NewBatch("KV")
+	b.Include = []string{"*.txt"}
+	b.Exclude = []string{"vendor", "sub/vendor.txt"}
+	b.Workers = 2
+	b.DryRun = true
+	results := b.Run(dir)
+	expect := []FileResult{
+		{Path: "a.txt", Changed: true},
+		{Path: "b.txt"},
+		{Path: "bad.txt", Err: ErrNotUTF8},
+		{Path: "bin.txt", Skipped: true},
+		{Path: "sub/d.txt", Changed: true},
+	}
+	for i := range expect {
+		expect[i].Path = filepath.Join(dir, filepath.FromSlash(expect[i].Path))
+	}
+	if !reflect.DeepEqual(results, expect) {
+		t.Errorf("expect %v, result %v", expect, results)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(content) != "ｶﾞｽ" {
+		t.Errorf("DryRun modified a.txt: %s", content)
+	}
+
+	b.DryRun = false
+	if results = b.Run(dir); !reflect.DeepEqual(results, expect) {
+		t.Errorf("expect %v, result %v", expect, results)
+	}
+	for name, content := range map[string]string{"a.txt": "ガス", "sub/d.txt": "アイウ", "c.md": "ｶﾞｽ", "vendor/e.txt": "ｱｲｳ"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if result, _ := os.ReadFile(path); string(result) != content {
+			t.Errorf("[%s] expect %s, result %s", name, content, result)
+		}
+	}
+	if info, _ := os.Stat(filepath.Join(dir, "a.txt")); info.Mode().Perm() != 0600 {
+		t.Errorf("expect %v, result %v", os.FileMode(0600), info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 7 {
+		t.Errorf("temporary files are left: %v", entries)
+	}
+
+	results = NewBatch("KV").Run(filepath.Join(dir, "none"))
+	if len(results) != 1 || results[0].Err == nil {
+		t.Errorf("expect an error, result %v", results)
+	}
+}
@@ -0,0 +1,161 @@
+package kanaco
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	// Class is a set of character classes.
+	Class int
+	// Segment is a run of characters of the same class.
+	Segment struct {
+		Class      Class
+		Text       string
+		Start, End int // byte offsets
+	}
+)
+
+const (
+	ClassHiragana           Class = 1 << iota // ぁ - ゖ ゝ ゞ ゟ
+	ClassKatakana                             // ァ - ヺ ヽ ヾ ヿ ㇰ - ㇿ
+	ClassHalfwidthKatakana                    // ｦ - ﾝ, ｶﾞ - ﾎﾟ
+	ClassKanaPunct                            // 、 。 〃 「 」 『 』 ゛ ゜ ゠ ・ ー
+	ClassHalfwidthKanaPunct                   // ｡ ｢ ｣ ､ ･ ｰ ﾞ ﾟ
+	ClassKanji                                // 漢 々 〆 〇
+	ClassASCIIDigit                           // 0 - 9
+	ClassASCIILetter                          // A - Z a - z
+	ClassASCIISymbol                          // ! - / : - @ [ - ` { - ~
+	ClassASCIISpace                           // U+0020 and ASCII white space
+	ClassFullwidthDigit                       // ０ - ９
+	ClassFullwidthLetter                      // Ａ - Ｚ ａ - ｚ
+	ClassFullwidthSymbol                      // ！ - ／ ： - ＠ ［ - ｀ ｛ - ～
+	ClassFullwidthSpace                       // U+3000
+	ClassOther                                // anything else
+
+	ClassASCIIAlnum     = ClassASCIIDigit | ClassASCIILetter
+	ClassASCII          = ClassASCIIAlnum | ClassASCIISymbol | ClassASCIISpace
+	ClassFullwidthAlnum = ClassFullwidthDigit | ClassFullwidthLetter
+	ClassFullwidthASCII = ClassFullwidthAlnum | ClassFullwidthSymbol
+)
+
+var classNames = []string{
+	"Hiragana", "Katakana", "HalfwidthKatakana", "KanaPunct", "HalfwidthKanaPunct", "Kanji",
+	"ASCIIDigit", "ASCIILetter", "ASCIISymbol", "ASCIISpace",
+	"FullwidthDigit", "FullwidthLetter", "FullwidthSymbol", "FullwidthSpace", "Other",
+}
+
+func (c Class) String() string {
+	names := []string{}
+	for i, name := range classNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+func IsHiragana(r rune) bool {
+	return ClassOf(r) == ClassHiragana
+}
+
+func IsKatakana(r rune) bool {
+	return ClassOf(r) == ClassKatakana
+}
+
+func IsHalfwidthKatakana(r rune) bool {
+	return ClassOf(r) == ClassHalfwidthKatakana
+}
+
+// IsKanaPunct reports whether r is a punctuation or sound mark used with
+// kana, in zenkaku or hankaku.
+func IsKanaPunct(r rune) bool {
+	return ClassOf(r)&(ClassKanaPunct|ClassHalfwidthKanaPunct) != 0
+}
+
+func IsKanji(r rune) bool {
+	return ClassOf(r) == ClassKanji
+}
+
+// IsFullwidthASCII reports whether r is one of the zenkaku forms of ASCII,
+// U+FF01 - U+FF5E.
+func IsFullwidthASCII(r rune) bool {
+	return ClassOf(r)&ClassFullwidthASCII != 0
+}
+
+// ClassOf returns the class of r.
+func ClassOf(r rune) Class {
+	switch {
+	case r < utf8.RuneSelf:
+		switch {
+		case r >= '0' && r <= '9':
+			return ClassASCIIDigit
+		case (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z'):
+			return ClassASCIILetter
+		case r >= 0x21 && r <= 0x7e:
+			return ClassASCIISymbol
+		case r == 0x20 || unicode.IsSpace(r):
+			return ClassASCIISpace
+		}
+	case (r >= 0x3041 && r <= 0x3096) || (r >= 0x309d && r <= 0x309f): // ぁ - ゖ, ゝ ゞ ゟ
+		return ClassHiragana
+	case (r >= 0x30a1 && r <= 0x30fa) || (r >= 0x30fd && r <= 0x30ff) || (r >= 0x31f0 && r <= 0x31ff): // ァ - ヺ, ヽ ヾ ヿ, ㇰ - ㇿ
+		return ClassKatakana
+	case (r >= 0xff66 && r <= 0xff6f) || (r >= 0xff71 && r <= 0xff9d): // ｦ - ｯ, ｱ - ﾝ
+		return ClassHalfwidthKatakana
+	case (r >= 0x3001 && r <= 0x3003) || (r >= 0x300c && r <= 0x300f) || r == 0x309b || r == 0x309c || r == 0x30a0 || r == 0x30fb || r == 0x30fc:
+		return ClassKanaPunct
+	case (r >= 0xff61 && r <= 0xff65) || r == 0xff70 || r == 0xff9e || r == 0xff9f: // ｡ - ･, ｰ, ﾞ ﾟ
+		return ClassHalfwidthKanaPunct
+	case r >= 0xff10 && r <= 0xff19:
+		return ClassFullwidthDigit
+	case (r >= 0xff21 && r <= 0xff3a) || (r >= 0xff41 && r <= 0xff5a):
+		return ClassFullwidthLetter
+	case r >= 0xff01 && r <= 0xff5e:
+		return ClassFullwidthSymbol
+	case r == 0x3000:
+		return ClassFullwidthSpace
+	case unicode.Is(unicode.Han, r):
+		return ClassKanji
+	}
+	return ClassOther
+}
+
+// classify returns the class of the character at the head of s and its
+// length. A hankaku kana and its voiced sound mark are a single character.
+func classify(s string) (Class, int) {
+	r, n := utf8.DecodeRuneInString(s)
+	cls := ClassOf(r)
+	if cls == ClassHalfwidthKatakana {
+		_, n = foldRune(s, 0)
+	}
+	return cls, n
+}
+
+// charCount returns the number of characters of s, a hankaku kana and its
+// voiced sound mark being one.
+func charCount(s string) int {
+	n := 0
+	for i := 0; i < len(s); n++ {
+		_, size := classify(s[i:])
+		i += size
+	}
+	return n
+}
+
+// Segments splits s into runs of characters of the same class.
+func Segments(s string) []Segment {
+	segs := []Segment{}
+	for i := 0; i < len(s); {
+		cls, n := classify(s[i:])
+		if last := len(segs) - 1; last >= 0 && segs[last].Class == cls {
+			segs[last].End = i + n
+			segs[last].Text = s[segs[last].Start:segs[last].End]
+		} else {
+			segs = append(segs, Segment{Class: cls, Text: s[i : i+n], Start: i, End: i + n})
+		}
+		i += n
+	}
+	return segs
+}
@@ -0,0 +1,52 @@
+package kanaco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassOf(t *testing.T) {
+	data := []struct {
+		r      rune
+		expect Class
+	}{
+		{'あ', ClassHiragana}, {'ゞ', ClassHiragana}, {'ア', ClassKatakana}, {'ㇰ', ClassKatakana},
+		{'ｱ', ClassHalfwidthKatakana}, {'ｰ', ClassHalfwidthKanaPunct}, {'ﾞ', ClassHalfwidthKanaPunct},
+		{'ー', ClassKanaPunct}, {'。', ClassKanaPunct}, {'漢', ClassKanji}, {'々', ClassKanji},
+		{'5', ClassASCIIDigit}, {'z', ClassASCIILetter}, {'~', ClassASCIISymbol}, {'\t', ClassASCIISpace},
+		{'５', ClassFullwidthDigit}, {'Ｚ', ClassFullwidthLetter}, {'！', ClassFullwidthSymbol},
+		{'　', ClassFullwidthSpace}, {'é', ClassOther}, {0, ClassOther},
+	}
+	for _, v := range data {
+		if result := ClassOf(v.r); result != v.expect {
+			t.Errorf("[%c] expect %s, result %s", v.r, v.expect, result)
+		}
+	}
+	if !IsHiragana('ゖ') || IsHiragana('ア') || !IsKatakana('ヶ') || !IsHalfwidthKatakana('ﾝ') || IsHalfwidthKatakana('ﾞ') {
+		t.Errorf("kana predicates")
+	}
+	if !IsKanaPunct('・') || !IsKanaPunct('･') || IsKanaPunct('!') || !IsFullwidthASCII('～') || IsFullwidthASCII('　') {
+		t.Errorf("punct predicates")
+	}
+	if s := (ClassKatakana | ClassFullwidthSpace).String(); s != "Katakana|FullwidthSpace" {
+		t.Errorf("expect %s, result %s", "Katakana|FullwidthSpace", s)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	segs := Segments("ｶﾞｽ代ガス　ABC１２")
+	expect := []Segment{
+		{ClassHalfwidthKatakana, "ｶﾞｽ", 0, 9},
+		{ClassKanji, "代", 9, 12},
+		{ClassKatakana, "ガス", 12, 18},
+		{ClassFullwidthSpace, "　", 18, 21},
+		{ClassASCIILetter, "ABC", 21, 24},
+		{ClassFullwidthDigit, "１２", 24, 30},
+	}
+	if !reflect.DeepEqual(segs, expect) {
+		t.Errorf("expect %v, result %v", expect, segs)
+	}
+	if segs := Segments(""); len(segs) != 0 {
+		t.Errorf("expect [], result %v", segs)
+	}
+}
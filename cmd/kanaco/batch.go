@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/elfincafe/kanaco"
+)
+
+// patterns is a flag that can be repeated.
+type patterns []string
+
+func (p *patterns) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *patterns) Set(v string) error {
+	*p = append(*p, v)
+	return nil
+}
+
+// runBatch converts the files under dirs in place, or prints their diffs with
+// --diff, and reports the results at the end.
+func (cfg *config) runBatch(dirs []string, stdout, stderr io.Writer) int {
+	b := &kanaco.Batch{
+		Include: cfg.include,
+		Exclude: cfg.exclude,
+		Workers: cfg.workers,
+		DryRun:  cfg.diff,
+		Convert: cfg.convert,
+	}
+	status := exitOK
+	sum := newSummary(cfg.mode, cfg.cv)
+	counts := map[string]int{}
+	for _, r := range b.Run(dirs...) {
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(stderr, "kanaco: %s: %v\n", r.Path, r.Err)
+			counts["failed"]++
+			status = exitError
+		case r.Skipped:
+			counts["skipped"]++
+		case !r.Changed:
+			counts["unchanged"]++
+		case cfg.diff:
+			counts["changed"]++
+			in, err := os.ReadFile(r.Path)
+			if err == nil {
+				in, err = cfg.decode(in)
+			}
+			if err == nil {
+				err = writeDiff(stdout, r.Path, cfg.cv, in, cfg.color)
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "kanaco: %s: %v\n", r.Path, err)
+				status = exitError
+				continue
+			}
+			sum.add(in)
+		default:
+			counts["changed"]++
+			fmt.Fprintln(stdout, r.Path)
+		}
+	}
+	if cfg.diff {
+		sum.write(stderr)
+	}
+	fmt.Fprintf(stderr, "kanaco: %d changed, %d unchanged, %d skipped, %d failed\n",
+		counts["changed"], counts["unchanged"], counts["skipped"], counts["failed"])
+	return status
+}
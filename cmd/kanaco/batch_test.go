@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"a.txt": "ｶﾞｽ\n", "b.txt": "ガス\n", "c.md": "ｶﾞｽ\n", "sub/d.txt": "\xff", "tmp/e.txt": "ｱ\n"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	a := filepath.Join(dir, "a.txt")
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args := []string{"-r", "--include", "*.txt", "--exclude", "tmp", "-j", "2"}
+	status := run(append(args, "--diff", dir), nil, stdout, stderr)
+	expect := "--- " + a + "\n+++ " + a + "\n@@ -1,1 +1,1 @@\n-ｶﾞｽ\n+ガス\n"
+	if status != exitError || stdout.String() != expect {
+		t.Errorf("expect %d %s, result %d %s", exitError, expect, status, stdout.String())
+	}
+	if !strings.HasSuffix(stderr.String(), "kanaco: 1 changed, 1 unchanged, 0 skipped, 1 failed\n") {
+		t.Errorf("invalid report %s", stderr.String())
+	}
+	if result, _ := os.ReadFile(a); string(result) != "ｶﾞｽ\n" {
+		t.Errorf("--diff modified %s", a)
+	}
+
+	stdout.Reset()
+	if status := run(append(args, dir), nil, stdout, stderr); status != exitError || stdout.String() != a+"\n" {
+		t.Errorf("expect %d %s, result %d %s", exitError, a, status, stdout.String())
+	}
+	for name, content := range map[string]string{"a.txt": "ガス\n", "c.md": "ｶﾞｽ\n", "tmp/e.txt": "ｱ\n"} {
+		if result, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name))); string(result) != content {
+			t.Errorf("[%s] expect %s, result %s", name, content, result)
+		}
+	}
+
+	for _, args := range [][]string{{"-r"}, {"-r", "-o", "x", dir}, {"--include", "*.txt", a}} {
+		if status := run(args, nil, stdout, stderr); status != exitUsage {
+			t.Errorf("%v: expect %d, result %d", args, exitUsage, status)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elfincafe/kanaco"
+)
+
+// Exit codes of check. An error wins over findings, so that a hook can tell
+// a file that was not checked from a file that needs converting.
+const (
+	exitFound      = 1
+	exitCheckError = 2
+)
+
+const stdinName = "<stdin>"
+
+// finding is a location whose conversion would change. Columns count
+// characters from 1, and the end is exclusive.
+type finding struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+}
+
+// runCheck reports the locations the mode would change without modifying
+// the files. It exits with 1 when there are any, and with 2 when a file
+// cannot be checked or the arguments are wrong.
+func runCheck(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("kanaco check", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cfg := config{}
+	from, format := "", ""
+	fs.StringVar(&cfg.mode, "m", "KV", "conversion `mode`, e.g. KV or as")
+	fs.StringVar(&from, "from", "utf-8", "`encoding` of the input, e.g. shift_jis, euc-jp")
+	fs.StringVar(&format, "format", "text", "report `format`: text, json or sarif")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kanaco check [-m mode] [--from enc] [--format text|json|sarif] [file ...]")
+		fs.PrintDefaults()
+		fmt.Fprintln(stderr, "exit status: 0 nothing to convert, 1 locations found, 2 error")
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	report, ok := reports[format]
+	if !ok {
+		fmt.Fprintf(stderr, "kanaco: unknown format %q\n", format)
+		return exitUsage
+	}
+	if err := cfg.setup(from, "", fs.Args()); err != nil {
+		fmt.Fprintf(stderr, "kanaco: %v\n", err)
+		return exitUsage
+	}
+
+	status := exitOK
+	findings := []finding{}
+	check := func(name string, in []byte) {
+		text, err := cfg.decode(in)
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %s: %v\n", name, err)
+			status = exitCheckError
+			return
+		}
+		findings = append(findings, locate(name, text, cfg.cv.Changes(text))...)
+	}
+	if len(fs.Args()) == 0 {
+		in, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			return exitCheckError
+		}
+		check(stdinName, in)
+	}
+	for _, path := range fs.Args() {
+		in, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			status = exitCheckError
+			continue
+		}
+		check(path, in)
+	}
+	if err := report(stdout, cfg.mode, findings); err != nil {
+		fmt.Fprintf(stderr, "kanaco: %v\n", err)
+		return exitCheckError
+	}
+	if status == exitOK && len(findings) > 0 {
+		return exitFound
+	}
+	return status
+}
+
+// locate converts the byte offsets of changes to lines and columns.
+func locate(name string, text []byte, changes []kanaco.Change) []finding {
+	lines := []int{0}
+	for i, b := range text {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	position := func(off int) (int, int) {
+		l := sort.Search(len(lines), func(i int) bool { return lines[i] > off }) - 1
+		return l + 1, utf8.RuneCount(text[lines[l]:off]) + 1
+	}
+	findings := make([]finding, 0, len(changes))
+	for _, c := range changes {
+		f := finding{File: name, Before: c.Before, After: c.After}
+		f.Line, f.Column = position(c.Start)
+		f.EndLine, f.EndColumn = position(c.End)
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+var reports = map[string]func(io.Writer, string, []finding) error{
+	"text":  reportText,
+	"json":  reportJSON,
+	"sarif": reportSARIF,
+}
+
+func reportText(w io.Writer, mode string, findings []finding) error {
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s -> %s\n", f.File, f.Line, f.Column, f.Before, f.After); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reportJSON(w io.Writer, mode string, findings []finding) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// sarifURI returns the URI of the artifact at path: the slash separated
+// path relative to the working directory, or a file URI when the path is
+// outside it.
+func sarifURI(path string) string {
+	if filepath.IsAbs(path) {
+		wd, err := os.Getwd()
+		rel, relErr := filepath.Rel(wd, path)
+		if err != nil || relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			p := filepath.ToSlash(path)
+			if !strings.HasPrefix(p, "/") {
+				p = "/" + p // C:/dir
+			}
+			return (&url.URL{Scheme: "file", Path: p}).String()
+		}
+		path = rel
+	}
+	return (&url.URL{Path: filepath.ToSlash(path)}).String()
+}
+
+// reportSARIF writes a SARIF 2.1.0 log with a fix for each finding.
+func reportSARIF(w io.Writer, mode string, findings []finding) error {
+	type object = map[string]interface{}
+	ruleID := "kanaco-" + mode
+	results := []object{}
+	for _, f := range findings {
+		location := object{"description": object{"text": "standard input"}}
+		if f.File != stdinName {
+			location = object{"uri": sarifURI(f.File)}
+		}
+		region := object{
+			"startLine": f.Line, "startColumn": f.Column,
+			"endLine": f.EndLine, "endColumn": f.EndColumn,
+			"snippet": object{"text": f.Before},
+		}
+		results = append(results, object{
+			"ruleId":  ruleID,
+			"level":   "warning",
+			"message": object{"text": fmt.Sprintf("%s -> %s", f.Before, f.After)},
+			"locations": []object{
+				{"physicalLocation": object{"artifactLocation": location, "region": region}},
+			},
+			"fixes": []object{{
+				"description": object{"text": fmt.Sprintf("Convert with mode %s", mode)},
+				"artifactChanges": []object{{
+					"artifactLocation": location,
+					"replacements": []object{
+						{"deletedRegion": region, "insertedContent": object{"text": f.After}},
+					},
+				}},
+			}},
+		})
+	}
+	log := object{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": []object{{
+			"tool": object{"driver": object{
+				"name":           "kanaco",
+				"informationUri": "https://github.com/elfincafe/kanaco",
+				"rules": []object{{
+					"id":               ruleID,
+					"shortDescription": object{"text": fmt.Sprintf("Text that mode %s converts", mode)},
+				}},
+			}},
+			"columnKind": "unicodeCodePoints",
+			"results":    results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	os.WriteFile(a, []byte("カナコ\nこれはｶﾞｽと\nＡＢＣ"), 0644)
+	os.WriteFile(b, []byte("カナコ\n"), 0644)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	status := run([]string{"check", "-m", "KVa", a, b}, nil, stdout, stderr)
+	expect := a + ":2:4: ｶﾞｽ -> ガス\n" + a + ":3:1: ＡＢＣ -> ABC\n"
+	if status != exitFound || stdout.String() != expect {
+		t.Errorf("expect %d %s, result %d %s", exitFound, expect, status, stdout.String())
+	}
+	if result, _ := os.ReadFile(a); string(result) != "カナコ\nこれはｶﾞｽと\nＡＢＣ" {
+		t.Errorf("check modified %s", a)
+	}
+
+	stdout.Reset()
+	if status := run([]string{"check", b}, nil, stdout, stderr); status != exitOK || stdout.Len() != 0 {
+		t.Errorf("expect %d, result %d %s", exitOK, status, stdout.String())
+	}
+	if status := run([]string{"check", filepath.Join(dir, "none.txt")}, nil, stdout, stderr); status != exitCheckError {
+		t.Errorf("expect %d, result %d", exitCheckError, status)
+	}
+	if status := run([]string{"check", a, filepath.Join(dir, "none.txt")}, nil, stdout, stderr); status != exitCheckError {
+		t.Errorf("expect %d, result %d", exitCheckError, status)
+	}
+	if status := run([]string{"check"}, strings.NewReader("\xff"), stdout, stderr); status != exitCheckError {
+		t.Errorf("expect %d, result %d", exitCheckError, status)
+	}
+	if status := run([]string{"check", "--format", "xml"}, nil, stdout, stderr); status != exitUsage {
+		t.Errorf("expect %d, result %d", exitUsage, status)
+	}
+}
+
+func TestCheckFormats(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	run([]string{"check", "--format", "json"}, strings.NewReader("aｶﾞ\n"), stdout, stderr)
+	findings := []finding{}
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil {
+		t.Fatal(err)
+	}
+	expect := finding{stdinName, 1, 2, 1, 4, "ｶﾞ", "ガ"}
+	if len(findings) != 1 || findings[0] != expect {
+		t.Errorf("expect %v, result %v", expect, findings)
+	}
+
+	stdout.Reset()
+	run([]string{"check", "--format", "sarif"}, strings.NewReader("aｶﾞ\n"), stdout, stderr)
+	log := struct {
+		Version string
+		Runs    []struct {
+			Results []struct {
+				RuleID    string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI         string
+							Description struct{ Text string }
+						}
+						Region struct{ StartLine, StartColumn, EndColumn int }
+					}
+				}
+			}
+		}
+	}{}
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("invalid log %s", stdout.String())
+	}
+	r := log.Runs[0].Results[0]
+	region := r.Locations[0].PhysicalLocation.Region
+	if r.RuleID != "kanaco-KV" || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "" || r.Locations[0].PhysicalLocation.ArtifactLocation.Description.Text != "standard input" || region.StartLine != 1 || region.StartColumn != 2 || region.EndColumn != 4 {
+		t.Errorf("invalid result %s", stdout.String())
+	}
+}
+
+func TestSARIFURI(t *testing.T) {
+	wd, _ := os.Getwd()
+	outside := filepath.Join(filepath.Dir(wd), "x y.txt")
+	data := []struct {
+		path, expect string
+	}{
+		{filepath.Join("dir", "a b.txt"), "dir/a%20b.txt"},
+		{filepath.Join(wd, "dir", "ｶﾅ#1.txt"), "dir/%EF%BD%B6%EF%BE%85%231.txt"},
+		{outside, (&url.URL{Scheme: "file", Path: filepath.ToSlash(outside)}).String()},
+		{"a:b.txt", "./a:b.txt"},
+	}
+	for _, v := range data {
+		if result := sarifURI(v.path); result != v.expect {
+			t.Errorf("[%s] expect %s, result %s", v.path, v.expect, result)
+		}
+	}
+}
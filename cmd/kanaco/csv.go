@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/elfincafe/kanaco"
+	"golang.org/x/text/transform"
+)
+
+// csvTransformer returns the transformer of the columns of --column. A column
+// is a number from 1 or a header name, followed by =mode to use a mode other
+// than -m.
+func (cfg *config) csvTransformer() (*kanaco.CSVTransformer, error) {
+	comma := ','
+	if cfg.tsv {
+		comma = '\t'
+	}
+	t := kanaco.NewCSVTransformer(comma)
+	t.Header = cfg.header
+	for _, col := range cfg.columns {
+		name, mode := col, cfg.mode
+		if i := strings.LastIndexByte(col, '='); i >= 0 {
+			name, mode = col[:i], col[i+1:]
+		}
+		if err := kanaco.CheckMode(mode); err != nil {
+			return nil, fmt.Errorf("--column %s: %v", col, err)
+		}
+		if n, err := strconv.Atoi(name); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("--column %s: columns are numbered from 1", col)
+			}
+			t.SetColumn(n-1, mode)
+		} else {
+			t.SetColumnName(name, mode)
+		}
+	}
+	return t, nil
+}
+
+// runCSV streams the records of each input to the output with the columns
+// converted.
+func (cfg *config) runCSV(files []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	t, err := cfg.csvTransformer()
+	if err != nil {
+		fmt.Fprintf(stderr, "kanaco: %v\n", err)
+		return exitUsage
+	}
+	w := stdout
+	if cfg.output != "" {
+		f, err := os.Create(cfg.output)
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			return exitError
+		}
+		defer f.Close()
+		w = f
+	}
+	if cfg.to != nil {
+		tw := transform.NewWriter(w, cfg.to.NewEncoder())
+		defer tw.Close()
+		w = tw
+	}
+	transformFile := func(name string, r io.Reader) error {
+		if cfg.from != nil {
+			r = transform.NewReader(r, cfg.from.NewDecoder())
+		}
+		if err := t.Transform(w, r); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	}
+	status := exitOK
+	if len(files) == 0 {
+		if err := transformFile(stdinName, stdin); err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			status = exitError
+		}
+	}
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err == nil {
+			err = transformFile(path, f)
+			f.Close()
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			status = exitError
+		}
+	}
+	return status
+}
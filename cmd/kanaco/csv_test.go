@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestCSV(t *testing.T) {
+	in := "kana,name,phone\r\n\"ﾔﾏﾀﾞ\",山田,０３－１２３４\r\n"
+	data := []struct {
+		args   []string
+		in     string
+		status int
+		out    string
+	}{
+		{[]string{"--csv", "--header", "--column", "kana", "--column", "3=a"}, in, exitOK, "kana,name,phone\r\n\"ヤマダ\",山田,03-1234\r\n"},
+		{[]string{"--csv", "--column", "1=H"}, "ｶﾅ,ｶﾅ\n", exitOK, "かな,ｶﾅ\n"},
+		{[]string{"--tsv", "-m", "a", "--column", "2"}, "１\t１\n", exitOK, "１\t1\n"},
+		{[]string{"--csv", "--column", "none"}, in, exitError, ""},
+		{[]string{"--csv", "--column", "0"}, in, exitUsage, ""},
+		{[]string{"--csv", "--column", "1=x"}, in, exitUsage, ""},
+		{[]string{"--csv"}, in, exitUsage, ""},
+		{[]string{"--column", "1"}, in, exitUsage, ""},
+		{[]string{"--csv", "--tsv", "--column", "1"}, in, exitUsage, ""},
+	}
+	for _, v := range data {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		status := run(v.args, strings.NewReader(v.in), stdout, stderr)
+		if status != v.status || stdout.String() != v.out {
+			t.Errorf("%v: expect %d %q, result %d %q (%s)", v.args, v.status, v.out, status, stdout.String(), stderr.String())
+		}
+	}
+
+	sjis, _ := japanese.ShiftJIS.NewEncoder().String("ｶﾅ,ＡＢ\n")
+	dir := t.TempDir()
+	a, out := filepath.Join(dir, "a.csv"), filepath.Join(dir, "out.csv")
+	os.WriteFile(a, []byte(sjis), 0644)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if status := run([]string{"--csv", "--column", "1", "--column", "2=a", "--from", "shift_jis", "-o", out, a}, nil, stdout, stderr); status != exitOK {
+		t.Errorf("expect %d, result %d %s", exitOK, status, stderr.String())
+	}
+	expect, _ := japanese.ShiftJIS.NewEncoder().String("カナ,AB\n")
+	if result, _ := os.ReadFile(out); string(result) != expect {
+		t.Errorf("expect %q, result %q", expect, result)
+	}
+}
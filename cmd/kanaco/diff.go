@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/elfincafe/kanaco"
+)
+
+const diffContext = 3
+
+const (
+	colorDel   = "\x1b[31m"
+	colorAdd   = "\x1b[32m"
+	colorReset = "\x1b[0m"
+	markOn     = "\x1b[7m"
+	markOff    = "\x1b[27m"
+)
+
+type (
+	// span is a changed range of a line in bytes.
+	span struct {
+		start, end int
+	}
+	// diffText is a text split into lines, with the changed spans of each
+	// line.
+	diffText struct {
+		lines  []string
+		starts []int
+		spans  [][]span
+		eol    bool // the last line ends with a line break
+	}
+	// summary counts the characters each mode letter changes.
+	summary struct {
+		files   int
+		letters []byte
+		cv      *kanaco.Converter
+		counts  []int
+	}
+)
+
+func newDiffText(text string) *diffText {
+	d := &diffText{eol: strings.HasSuffix(text, "\n")}
+	d.lines = strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	if text == "" {
+		d.lines = nil
+	}
+	d.spans = make([][]span, len(d.lines))
+	off := 0
+	for i, l := range d.lines {
+		d.starts = append(d.starts, off)
+		d.lines[i] = strings.TrimSuffix(l, "\n")
+		off += len(l)
+	}
+	return d
+}
+
+// mark records the changed range [start, end) of the text.
+func (d *diffText) mark(start, end int) {
+	i := sort.Search(len(d.starts), func(i int) bool { return d.starts[i] > start }) - 1
+	if i < 0 {
+		return
+	}
+	d.spans[i] = append(d.spans[i], span{start - d.starts[i], end - d.starts[i]})
+}
+
+// line returns the i-th line, highlighting its changed spans when color is
+// set.
+func (d *diffText) line(i int, color string) string {
+	if color == "" {
+		return d.lines[i]
+	}
+	buf := strings.Builder{}
+	buf.WriteString(color)
+	off := 0
+	for _, s := range d.spans[i] {
+		end := s.end
+		if end > len(d.lines[i]) {
+			end = len(d.lines[i])
+		}
+		buf.WriteString(d.lines[i][off:s.start])
+		buf.WriteString(markOn + d.lines[i][s.start:end] + markOff)
+		off = end
+	}
+	buf.WriteString(d.lines[i][off:])
+	buf.WriteString(colorReset)
+	return buf.String()
+}
+
+// writeDiff writes the unified diff between text and its conversion. A
+// conversion never changes line breaks, so the lines of both texts pair up.
+func writeDiff(w io.Writer, name string, cv *kanaco.Converter, text []byte, highlight bool) error {
+	out, m := cv.ConvertWithMap(text)
+	a, b := newDiffText(string(text)), newDiffText(string(out))
+	for _, c := range cv.Changes(text) {
+		a.mark(c.Start, c.End)
+		b.mark(m.ToConverted(c.Start), m.ToConverted(c.End))
+	}
+	if len(a.lines) != len(b.lines) {
+		return fmt.Errorf("the conversion changed line breaks")
+	}
+	changed := []int{}
+	for i := range a.lines {
+		if a.lines[i] != b.lines[i] {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	del, add := "", ""
+	if highlight {
+		del, add = colorDel, colorAdd
+	}
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", name, name)
+	for i := 0; i < len(changed); {
+		// a hunk covers the changed lines whose contexts overlap
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*diffContext {
+			j++
+		}
+		start, end := changed[i]-diffContext, changed[j]+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(a.lines) {
+			end = len(a.lines)
+		}
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for l := start; l < end; {
+			if a.lines[l] == b.lines[l] {
+				writeLine(&buf, " ", a.lines[l], l == len(a.lines)-1 && !a.eol)
+				l++
+				continue
+			}
+			k := l
+			for k < end && a.lines[k] != b.lines[k] {
+				k++
+			}
+			for n := l; n < k; n++ {
+				writeLine(&buf, "-", a.line(n, del), n == len(a.lines)-1 && !a.eol)
+			}
+			for n := l; n < k; n++ {
+				writeLine(&buf, "+", b.line(n, add), n == len(b.lines)-1 && !b.eol)
+			}
+			l = k
+		}
+		i = j + 1
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func writeLine(buf *strings.Builder, prefix, line string, noEOL bool) {
+	buf.WriteString(prefix + line + "\n")
+	if noEOL {
+		buf.WriteString("\\ No newline at end of file\n")
+	}
+}
+
+func newSummary(mode string, cv *kanaco.Converter) *summary {
+	s := &summary{cv: cv}
+	for _, m := range []byte(mode) {
+		if strings.IndexByte(string(s.letters), m) < 0 {
+			s.letters = append(s.letters, m)
+			s.counts = append(s.counts, 0)
+		}
+	}
+	return s
+}
+
+// add counts the characters of text each mode letter takes part in
+// changing.
+func (s *summary) add(text []byte) {
+	changed := false
+	counts := s.cv.ChangeCounts(text)
+	for i, m := range s.letters {
+		s.counts[i] += counts[m]
+		changed = changed || counts[m] > 0
+	}
+	if changed {
+		s.files++
+	}
+}
+
+func (s *summary) write(w io.Writer) {
+	fmt.Fprintf(w, "kanaco: %d file(s) changed\n", s.files)
+	for i, m := range s.letters {
+		fmt.Fprintf(w, "  %c: %d character(s)\n", m, s.counts[i])
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	lines := []string{"ｶﾞｽ", "1", "2", "3", "4", "5", "6", "7", "8", "9", "ＡＢ"}
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	os.WriteFile(a, []byte(strings.Join(lines, "\n")), 0644)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	status := run([]string{"-m", "KVa", "--in-place", "--diff", a}, nil, stdout, stderr)
+	expect := "--- " + a + "\n+++ " + a + "\n" +
+		"@@ -1,4 +1,4 @@\n-ｶﾞｽ\n+ガス\n 1\n 2\n 3\n" +
+		"@@ -8,4 +8,4 @@\n 7\n 8\n 9\n-ＡＢ\n\\ No newline at end of file\n+AB\n\\ No newline at end of file\n"
+	if status != exitOK || stdout.String() != expect {
+		t.Errorf("expect %d\n%s\nresult %d\n%s", exitOK, expect, status, stdout.String())
+	}
+	summary := "kanaco: 1 file(s) changed\n  K: 2 character(s)\n  V: 0 character(s)\n  a: 2 character(s)\n"
+	if stderr.String() != summary {
+		t.Errorf("expect\n%s\nresult\n%s", summary, stderr.String())
+	}
+	if result, _ := os.ReadFile(a); string(result) != strings.Join(lines, "\n") {
+		t.Errorf("--diff modified %s", a)
+	}
+
+	stdout.Reset()
+	run([]string{"--diff", "--highlight"}, strings.NewReader("はｶﾞｽと\n1\n"), stdout, stderr)
+	expect = "--- <stdin>\n+++ <stdin>\n@@ -1,2 +1,2 @@\n" +
+		"-" + colorDel + "は" + markOn + "ｶﾞｽ" + markOff + "と" + colorReset + "\n" +
+		"+" + colorAdd + "は" + markOn + "ガス" + markOff + "と" + colorReset + "\n 1\n"
+	if stdout.String() != expect {
+		t.Errorf("expect %q, result %q", expect, stdout.String())
+	}
+
+	stdout.Reset()
+	if status := run([]string{"--diff"}, strings.NewReader("カナ\n"), stdout, stderr); status != exitOK || stdout.Len() != 0 {
+		t.Errorf("expect %d, result %d %s", exitOK, status, stdout.String())
+	}
+	if status := run([]string{"--highlight"}, strings.NewReader(""), stdout, stderr); status != exitUsage {
+		t.Errorf("expect %d, result %d", exitUsage, status)
+	}
+}
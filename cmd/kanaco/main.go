@@ -0,0 +1,335 @@
+// Command kanaco converts kana, alphabets, numbers and spaces of text files
+// between zenkaku and hankaku.
+//
+// Usage:
+//
+//	kanaco -m mode [-o file | --in-place [--backup suffix]] [--from enc] [--to enc] [file ...]
+//	kanaco -m mode --diff [--highlight] [--from enc] [file ...]
+//	kanaco -m mode -r [--include glob] [--exclude glob] [-j n] [--diff | --to enc] [--from enc] dir ...
+//	kanaco -m mode --csv|--tsv [--header] --column col[=mode] ... [-o file] [--from enc] [--to enc] [file ...]
+//	kanaco check -m mode [--from enc] [--format text|json|sarif] [file ...]
+//
+// With no files, kanaco reads the standard input. --diff prints a unified
+// diff of the conversion instead and writes no files. With -r, kanaco
+// converts the files under the directories in place. With --csv or --tsv,
+// kanaco converts only the selected columns. The check subcommand
+// reports the locations the mode would change without modifying the files,
+// and exits with 1 when there are any and with 2 on errors.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elfincafe/kanaco"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+var errInvalidInput = errors.New("invalid input")
+
+type config struct {
+	mode    string
+	output  string
+	inPlace bool
+	backup  string
+	diff    bool
+	color   bool
+	recurse bool
+	include patterns
+	exclude patterns
+	workers int
+	csv     bool
+	tsv     bool
+	header  bool
+	columns patterns
+	from    encoding.Encoding
+	to      encoding.Encoding
+	cv      *kanaco.Converter
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "check" {
+		return runCheck(args[1:], stdin, stdout, stderr)
+	}
+	fs := flag.NewFlagSet("kanaco", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	cfg := config{}
+	from, to := "", ""
+	fs.StringVar(&cfg.mode, "m", "KV", "conversion `mode`, e.g. KV or as")
+	fs.StringVar(&cfg.output, "o", "", "write the output to `file` instead of the standard output")
+	fs.BoolVar(&cfg.inPlace, "in-place", false, "overwrite the input files")
+	fs.StringVar(&cfg.backup, "backup", "", "keep the original of each file overwritten by --in-place with this `suffix`")
+	fs.StringVar(&from, "from", "utf-8", "`encoding` of the input, e.g. shift_jis, euc-jp")
+	fs.StringVar(&to, "to", "", "`encoding` of the output, the same as --from by default")
+	fs.BoolVar(&cfg.diff, "diff", false, "print a unified diff of the conversion and a summary instead of writing files")
+	fs.BoolVar(&cfg.color, "highlight", false, "highlight the changed characters of --diff")
+	fs.BoolVar(&cfg.recurse, "r", false, "convert the files under the directories in place")
+	fs.Var(&cfg.include, "include", "convert only the files matching `glob` with -r, e.g. *.txt (repeatable)")
+	fs.Var(&cfg.exclude, "exclude", "skip the files and directories matching `glob` with -r (repeatable)")
+	fs.IntVar(&cfg.workers, "j", 0, "convert `n` files at once with -r, the number of CPUs by default")
+	fs.BoolVar(&cfg.csv, "csv", false, "convert the columns selected by --column of CSV")
+	fs.BoolVar(&cfg.tsv, "tsv", false, "convert the columns selected by --column of TSV")
+	fs.BoolVar(&cfg.header, "header", false, "the first record of --csv or --tsv is a header")
+	fs.Var(&cfg.columns, "column", "convert the `column`, a number from 1 or a header name, optionally with its own mode as name=mode (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kanaco [-m mode] [-o file | --in-place [--backup suffix]] [--from enc] [--to enc] [file ...]")
+		fmt.Fprintln(stderr, "       kanaco [-m mode] --diff [--highlight] [--from enc] [file ...]")
+		fmt.Fprintln(stderr, "       kanaco [-m mode] -r [--include glob] [--exclude glob] [-j n] [--diff | --to enc] [--from enc] dir ...")
+		fmt.Fprintln(stderr, "       kanaco [-m mode] --csv|--tsv [--header] --column col[=mode] ... [-o file] [--from enc] [--to enc] [file ...]")
+		fmt.Fprintln(stderr, "       kanaco check [-m mode] [--from enc] [--format text|json|sarif] [file ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if err := cfg.setup(from, to, fs.Args()); err != nil {
+		fmt.Fprintf(stderr, "kanaco: %v\n", err)
+		return exitUsage
+	}
+
+	if cfg.recurse {
+		return cfg.runBatch(fs.Args(), stdout, stderr)
+	}
+	if cfg.csv || cfg.tsv {
+		return cfg.runCSV(fs.Args(), stdin, stdout, stderr)
+	}
+	if cfg.diff {
+		return cfg.runDiff(fs.Args(), stdin, stdout, stderr)
+	}
+	if len(fs.Args()) == 0 {
+		in, err := io.ReadAll(stdin)
+		if err == nil {
+			err = cfg.write(cfg.output, in, stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	status := exitOK
+	out := []byte{}
+	for _, path := range fs.Args() {
+		in, err := os.ReadFile(path)
+		if err == nil && cfg.inPlace {
+			err = cfg.overwrite(path, in)
+		} else if err == nil {
+			var b []byte
+			b, err = cfg.convert(in)
+			out = append(out, b...)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %s: %v\n", path, err)
+			status = exitError
+		}
+	}
+	if !cfg.inPlace {
+		if err := cfg.put(cfg.output, out, stdout); err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			return exitError
+		}
+	}
+	return status
+}
+
+// setup checks the options and sets up cfg. to is empty when it defaults to
+// from.
+func (cfg *config) setup(from, to string, files []string) error {
+	if err := kanaco.CheckMode(cfg.mode); err != nil {
+		return err
+	}
+	if cfg.inPlace && cfg.output != "" {
+		return errors.New("-o and --in-place are exclusive")
+	}
+	if cfg.inPlace && len(files) == 0 {
+		return errors.New("--in-place needs files")
+	}
+	if cfg.backup != "" && !cfg.inPlace {
+		return errors.New("--backup needs --in-place")
+	}
+	if cfg.color && !cfg.diff {
+		return errors.New("--highlight needs --diff")
+	}
+	if cfg.diff && (cfg.output != "" || to != "") {
+		return errors.New("--diff cannot be used with -o or --to")
+	}
+	if cfg.recurse && (len(files) == 0 || cfg.output != "" || cfg.backup != "") {
+		return errors.New("-r needs directories and cannot be used with -o or --backup")
+	}
+	if !cfg.recurse && (len(cfg.include) > 0 || len(cfg.exclude) > 0 || cfg.workers != 0) {
+		return errors.New("--include, --exclude and -j need -r")
+	}
+	if cfg.csv || cfg.tsv {
+		if cfg.csv && cfg.tsv {
+			return errors.New("--csv and --tsv are exclusive")
+		}
+		if cfg.inPlace || cfg.diff || cfg.recurse {
+			return errors.New("--csv and --tsv cannot be used with --in-place, --diff or -r")
+		}
+		if len(cfg.columns) == 0 {
+			return errors.New("--csv and --tsv need --column")
+		}
+	} else if len(cfg.columns) > 0 || cfg.header {
+		return errors.New("--column and --header need --csv or --tsv")
+	}
+	if to == "" {
+		to = from
+	}
+	var err error
+	if cfg.from, err = encodingOf(from); err != nil {
+		return err
+	}
+	if cfg.to, err = encodingOf(to); err != nil {
+		return err
+	}
+	cfg.cv = kanaco.NewConverter(cfg.mode)
+	return nil
+}
+
+// encodingOf returns the encoding of name, or nil for UTF-8.
+func encodingOf(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(name) {
+	case "utf-8", "utf8":
+		return nil, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown encoding %q", name)
+	}
+	return enc, nil
+}
+
+// convert decodes in, converts it with the mode and encodes the result.
+func (cfg *config) convert(in []byte) ([]byte, error) {
+	text, err := cfg.decode(in)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.encode(cfg.cv.Byte(text))
+}
+
+func (cfg *config) decode(in []byte) ([]byte, error) {
+	if cfg.from == nil {
+		if !utf8.Valid(in) {
+			return nil, fmt.Errorf("%w: not UTF-8", errInvalidInput)
+		}
+		return in, nil
+	}
+	text, err := cfg.from.NewDecoder().Bytes(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidInput, err)
+	}
+	if bytes.ContainsRune(text, utf8.RuneError) {
+		return nil, fmt.Errorf("%w: not in the encoding of --from", errInvalidInput)
+	}
+	return text, nil
+}
+
+func (cfg *config) encode(text []byte) ([]byte, error) {
+	if cfg.to == nil {
+		return text, nil
+	}
+	out, err := cfg.to.NewEncoder().Bytes(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode the output: %v", err)
+	}
+	return out, nil
+}
+
+// runDiff prints the diff of each file and a summary of the changes by mode
+// letter on stderr.
+func (cfg *config) runDiff(files []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	status := exitOK
+	sum := newSummary(cfg.mode, cfg.cv)
+	diff := func(name string, in []byte) {
+		text, err := cfg.decode(in)
+		if err == nil {
+			err = writeDiff(stdout, name, cfg.cv, text, cfg.color)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %s: %v\n", name, err)
+			status = exitError
+			return
+		}
+		sum.add(text)
+	}
+	if len(files) == 0 {
+		in, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			return exitError
+		}
+		diff(stdinName, in)
+	}
+	for _, path := range files {
+		in, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "kanaco: %v\n", err)
+			status = exitError
+			continue
+		}
+		diff(path, in)
+	}
+	sum.write(stderr)
+	return status
+}
+
+// write converts in and puts it to path, or w when path is empty.
+func (cfg *config) write(path string, in []byte, w io.Writer) error {
+	out, err := cfg.convert(in)
+	if err != nil {
+		return err
+	}
+	return cfg.put(path, out, w)
+}
+
+func (cfg *config) put(path string, out []byte, w io.Writer) error {
+	if path == "" {
+		_, err := w.Write(out)
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
+// overwrite converts the file at path in place, keeping its permissions and
+// the original with the backup suffix.
+func (cfg *config) overwrite(path string, in []byte) error {
+	out, err := cfg.convert(in)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(in, out) {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if cfg.backup != "" {
+		if err := os.WriteFile(path+cfg.backup, in, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, out, info.Mode().Perm())
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestRun(t *testing.T) {
+	sjis, _ := japanese.ShiftJIS.NewEncoder().String("ｶﾅｺ　ＡＢＣ")
+	data := []struct {
+		args   []string
+		in     string
+		status int
+		out    string
+	}{
+		{[]string{}, "ｶﾞｽ", exitOK, "ガス"},
+		{[]string{"-m", "as"}, "ｶﾅｺ　ＡＢＣ１", exitOK, "ｶﾅｺ ABC1"},
+		{[]string{"-m", "KVas", "--from", "shift_jis", "--to", "utf-8"}, sjis, exitOK, "カナコ ABC"},
+		{[]string{"-m", "x"}, "", exitUsage, ""},
+		{[]string{"--from", "nowhere"}, "", exitUsage, ""},
+		{[]string{"--in-place"}, "", exitUsage, ""},
+		{[]string{"--diff", "-o", "out.txt"}, "ｶﾞｽ", exitUsage, ""},
+		{[]string{"--diff", "--to", "shift_jis"}, "ｶﾞｽ", exitUsage, ""},
+		{[]string{}, "\xff", exitError, ""},
+		{[]string{"--to", "shift_jis"}, "😀", exitError, ""},
+	}
+	for _, v := range data {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		status := run(v.args, strings.NewReader(v.in), stdout, stderr)
+		if status != v.status || stdout.String() != v.out {
+			t.Errorf("%v: expect %d %s, result %d %s (%s)", v.args, v.status, v.out, status, stdout.String(), stderr.String())
+		}
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	os.WriteFile(a, []byte("ｱｲｳ\n"), 0600)
+	os.WriteFile(b, []byte("ｴｵ\n"), 0600)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if status := run([]string{a, b}, nil, stdout, stderr); status != exitOK || stdout.String() != "アイウ\nエオ\n" {
+		t.Errorf("expect %s, result %d %s", "アイウ\nエオ\n", status, stdout.String())
+	}
+
+	out := filepath.Join(dir, "out.txt")
+	if status := run([]string{"-m", "H", "-o", out, a}, nil, stdout, stderr); status != exitOK {
+		t.Errorf("-o: expect %d, result %d %s", exitOK, status, stderr.String())
+	}
+	if result, _ := os.ReadFile(out); string(result) != "あいう\n" {
+		t.Errorf("-o: expect %s, result %s", "あいう\n", result)
+	}
+
+	if status := run([]string{"--in-place", "--backup", ".orig", a, filepath.Join(dir, "none.txt")}, nil, stdout, stderr); status != exitError {
+		t.Errorf("--in-place: expect %d, result %d", exitError, status)
+	}
+	if result, _ := os.ReadFile(a); string(result) != "アイウ\n" {
+		t.Errorf("--in-place: expect %s, result %s", "アイウ\n", result)
+	}
+	if result, _ := os.ReadFile(a + ".orig"); string(result) != "ｱｲｳ\n" {
+		t.Errorf("--backup: expect %s, result %s", "ｱｲｳ\n", result)
+	}
+	if info, _ := os.Stat(a); info.Mode().Perm() != 0600 {
+		t.Errorf("--in-place: expect %v, result %v", os.FileMode(0600), info.Mode().Perm())
+	}
+}
@@ -0,0 +1,144 @@
+package kanaco
+
+import (
+	"bytes"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	Strength int
+	// Collator builds binary comparable sort keys in the gojūon order,
+	// following the levels of JIS X 4061.
+	Collator struct {
+		Strength Strength
+	}
+)
+
+const (
+	Primary    Strength = iota + 1 // letters: width, kana type, size and voicing are ignored
+	Secondary                      // voicing: は < ば < ぱ, long vowels and iteration marks
+	Tertiary                       // size: ぁ < あ
+	Quaternary                     // kana type, width and case: あ < ア < ｱ, a < ａ < A < Ａ
+)
+
+// kanaVowels maps a zenkaku katakana without voicing to the vowel of its
+// column.
+var kanaVowels = map[rune]rune{}
+
+func init() {
+	for _, col := range []string{"アカサタナハマヤラワ", "イキシチニヒミリヰ", "ウクスツヌフムユルヴ", "エケセテネヘメレヱ", "オコソトノホモヨロヲ"} {
+		rs := []rune(col)
+		for _, r := range rs {
+			kanaVowels[r] = rs[0]
+		}
+	}
+}
+
+// kanaVowel returns the vowel of the kana r, which may be hiragana, small or
+// voiced, as a zenkaku katakana.
+func kanaVowel(r rune) (rune, bool) {
+	k, _ := foldRune(string(r), FoldKana|FoldSmall|FoldVoiced)
+	v, ok := kanaVowels[k]
+	return v, ok
+}
+
+func NewCollator(strength Strength) *Collator {
+	return &Collator{Strength: strength}
+}
+
+// SortKey returns the sort key of s at the Quaternary strength.
+func SortKey(s string) []byte {
+	return NewCollator(Quaternary).Key(s)
+}
+
+// Sort sorts ss in the gojūon order at the Quaternary strength.
+func Sort(ss []string) {
+	NewCollator(Quaternary).Sort(ss)
+}
+
+// Key returns the sort key of s. Keys compare with bytes.Compare in the same
+// order as the strings compare with Compare.
+func (cl *Collator) Key(s string) []byte {
+	primary := []byte{}
+	levels := [3][]byte{}
+	prev := rune(-1)
+	for i := 0; i < len(s); {
+		p, n := foldRune(s[i:], FoldAll)
+		seg := s[i : i+n]
+		i += n
+		if p < 0 {
+			continue
+		}
+		raw, _ := utf8.DecodeRuneInString(seg)
+		w := [3]byte{1, 2, 1}
+		if v, _ := foldRune(seg, FoldAll&^FoldVoiced); v != p {
+			w[0] = 2
+			if k, _ := foldRune(seg, FoldAll&^(FoldVoiced|FoldSmall)); k >= 'ハ' && k <= 'ポ' && (k-'ハ')%3 == 2 {
+				w[0] = 3
+			}
+		}
+		if sm, _ := foldRune(seg, FoldAll&^FoldSmall); sm != p {
+			w[1] = 1
+		}
+		if p == 'ー' && prev >= 0 {
+			if v, ok := kanaVowel(prev); ok {
+				p = v
+				w[0] = 4
+			}
+		} else if p == 'ヽ' && prev >= 0 {
+			p = prev
+			w[0] += 4
+		}
+		switch {
+		case raw >= 0x3041 && raw <= 0x309f: // ぁ - ゟ
+			w[2] = 1
+		case raw >= 0x30a0 && raw <= 0x30ff: // ゠ - ヿ
+			w[2] = 2
+		case raw >= 0xff61 && raw <= 0xff9f: // ｡ - ﾟ
+			w[2] = 3
+		default:
+			if raw == 0x3000 || (raw >= 0xff01 && raw <= 0xff5e) {
+				w[2]++
+			}
+			if unicode.IsUpper(raw) {
+				w[2] += 2
+			}
+		}
+		prev = p
+		primary = append(primary, byte((p+1)>>16), byte((p+1)>>8), byte(p+1))
+		for l := range levels {
+			levels[l] = append(levels[l], w[l])
+		}
+	}
+	key := primary
+	for l := 0; l < len(levels) && Strength(l+2) <= cl.Strength; l++ {
+		if l == 0 {
+			key = append(key, 0, 0, 0)
+		} else {
+			key = append(key, 0)
+		}
+		key = append(key, levels[l]...)
+	}
+	return key
+}
+
+// Compare compares a and b in the gojūon order and returns -1, 0 or +1.
+func (cl *Collator) Compare(a, b string) int {
+	return bytes.Compare(cl.Key(a), cl.Key(b))
+}
+
+// Sort sorts ss in the gojūon order. Strings with the same key keep their
+// order.
+func (cl *Collator) Sort(ss []string) {
+	keys := make(map[string][]byte, len(ss))
+	for _, s := range ss {
+		if _, ok := keys[s]; !ok {
+			keys[s] = cl.Key(s)
+		}
+	}
+	sort.SliceStable(ss, func(i, j int) bool {
+		return bytes.Compare(keys[ss[i]], keys[ss[j]]) < 0
+	})
+}
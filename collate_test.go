@@ -0,0 +1,45 @@
+package kanaco
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	ss := []string{"パン", "はん", "ﾊﾝ", "ばん", "ハン", "はんい", "ハーブ", "はあぶ", "きょう", "きよう", "ABC", "abc", "ａｂｃ", "いすゞ", "いすず", "いすす"}
+	expect := []string{"abc", "ａｂｃ", "ABC", "いすす", "いすず", "いすゞ", "きょう", "きよう", "はあぶ", "ハーブ", "はん", "ハン", "ﾊﾝ", "ばん", "パン", "はんい"}
+	Sort(ss)
+	if !reflect.DeepEqual(ss, expect) {
+		t.Errorf("expect %v, result %v", expect, ss)
+	}
+}
+
+func TestCollatorStrength(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		strength Strength
+		expect   int
+	}{
+		{"はん", "パン", Primary, 0},
+		{"はん", "パン", Secondary, -1},
+		{"ぱん", "パン", Secondary, 0},
+		{"ぱん", "パン", Tertiary, 0},
+		{"ぱん", "パン", Quaternary, -1},
+		{"きょう", "きよう", Secondary, 0},
+		{"きょう", "きよう", Tertiary, -1},
+		{"カー", "カア", Primary, 0},
+		{"カー", "カア", Secondary, 1},
+		{"カ", "カア", Primary, -1},
+		{"ｶﾞｽ", "ガス", Tertiary, 0},
+		{"ｶﾞｽ", "ガス", Quaternary, 1},
+	}
+	for _, tt := range tests {
+		if result := NewCollator(tt.strength).Compare(tt.a, tt.b); result != tt.expect {
+			t.Errorf("%s, %s (%d): expect %d, result %d", tt.a, tt.b, tt.strength, tt.expect, result)
+		}
+	}
+	if !bytes.Equal(SortKey("ガス"), NewCollator(Quaternary).Key("ガス")) {
+		t.Error("SortKey: expect Quaternary key")
+	}
+}
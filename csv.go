@@ -0,0 +1,205 @@
+package kanaco
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// CSVTransformer converts selected columns of CSV or TSV records. The
+	// records are streamed, and the quoting of the fields, the line endings
+	// and the blank lines are kept.
+	CSVTransformer struct {
+		Comma   rune // field delimiter, ',' by default
+		Header  bool // the first record names the columns and is not converted
+		byIndex map[int]*Converter
+		byName  map[string]*Converter
+	}
+	// lineBuffer records the lines read through it, so that the original
+	// text of a record can be examined after csv.Reader parses it.
+	lineBuffer struct {
+		r     io.Reader
+		lines [][]byte // the last line may be incomplete
+		first int      // line number of lines[0], from 1
+	}
+)
+
+var ErrCSVColumn = errors.New("kanaco: no such column")
+
+const bom = "\ufeff"
+
+func NewCSVTransformer(comma rune) *CSVTransformer {
+	return &CSVTransformer{Comma: comma, byIndex: map[int]*Converter{}, byName: map[string]*Converter{}}
+}
+
+// SetColumn converts the column at index, from 0, with mode.
+func (t *CSVTransformer) SetColumn(index int, mode string, opts ...Option) {
+	t.byIndex[index] = NewConverter(mode, opts...)
+}
+
+// SetColumnName converts the column named name in the header with mode. It
+// sets Header.
+func (t *CSVTransformer) SetColumnName(name, mode string, opts ...Option) {
+	t.byName[name] = NewConverter(mode, opts...)
+	t.Header = true
+}
+
+// Transform reads records from r and writes them to w with the selected
+// columns converted. It returns an error wrapping ErrCSVColumn when a column
+// name is not in the header.
+func (t *CSVTransformer) Transform(w io.Writer, r io.Reader) error {
+	comma := t.Comma
+	if comma == 0 {
+		comma = ','
+	}
+	lb := &lineBuffer{r: r, first: 1}
+	cr := csv.NewReader(lb)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	bw := bufio.NewWriter(w)
+	cols := map[int]*Converter{}
+	for i, cv := range t.byIndex {
+		cols[i] = cv
+	}
+	next := 1 // the line after the last record
+	for n := 0; ; n++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		start, _ := cr.FieldPos(0)
+		for ; next < start; next++ {
+			bw.Write(lb.line(next)) // blank lines
+		}
+		last := len(record) - 1
+		end, _ := cr.FieldPos(last)
+		end += strings.Count(record[last], "\n")
+		eol := lb.line(end)
+		eol = eol[len(bytes.TrimRight(eol, "\r\n")):]
+		if n == 0 && t.Header {
+			if err := t.resolve(record, cols); err != nil {
+				return err
+			}
+		}
+		raw := []byte{}
+		starts := map[int]int{} // offsets of the lines in raw
+		for l := start; l <= end; l++ {
+			starts[l] = len(raw)
+			raw = append(raw, lb.line(l)...)
+		}
+		raw = raw[:len(raw)-len(eol)]
+		for i, field := range record {
+			l, c := cr.FieldPos(i)
+			from, to := starts[l]+c-1, len(raw)
+			if i < last {
+				nl, nc := cr.FieldPos(i + 1)
+				to = starts[nl] + nc - 1 - utf8.RuneLen(comma)
+			}
+			if i > 0 {
+				bw.WriteRune(comma)
+			}
+			cv, ok := cols[i]
+			if !ok || (n == 0 && t.Header) {
+				bw.Write(raw[from:to])
+				continue
+			}
+			if conv := cv.String(field); conv != field {
+				quoted := from < to && raw[from] == '"'
+				writeCSVField(bw, conv, comma, quoted, string(eol) == "\r\n")
+				continue
+			}
+			bw.Write(raw[from:to])
+		}
+		bw.Write(eol)
+		next = end + 1
+		lb.discard(next)
+	}
+	for ; next < lb.first+len(lb.lines); next++ {
+		bw.Write(lb.line(next))
+	}
+	return bw.Flush()
+}
+
+// resolve adds the converters of the column names in header to cols.
+func (t *CSVTransformer) resolve(header []string, cols map[int]*Converter) error {
+	for name, cv := range t.byName {
+		found := false
+		for i, h := range header {
+			if strings.TrimPrefix(h, bom) == name {
+				cols[i] = cv
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %q", ErrCSVColumn, name)
+		}
+	}
+	return nil
+}
+
+// writeCSVField writes a converted field, quoting it when it was quoted or
+// needs to be.
+func writeCSVField(w *bufio.Writer, field string, comma rune, quoted, crlf bool) {
+	if !quoted && !strings.ContainsRune(field, comma) && !strings.ContainsAny(field, "\"\r\n") {
+		w.WriteString(field)
+		return
+	}
+	field = strings.ReplaceAll(field, `"`, `""`)
+	if crlf {
+		field = strings.ReplaceAll(field, "\n", "\r\n")
+	}
+	w.WriteString(`"` + field + `"`)
+}
+
+func (lb *lineBuffer) Read(p []byte) (int, error) {
+	n, err := lb.r.Read(p)
+	data := p[:n]
+	for len(data) > 0 {
+		last := len(lb.lines) - 1
+		if last < 0 || bytes.HasSuffix(lb.lines[last], []byte("\n")) {
+			lb.lines = append(lb.lines, nil)
+			last++
+		}
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			lb.lines[last] = append(lb.lines[last], data...)
+			break
+		}
+		lb.lines[last] = append(lb.lines[last], data[:i+1]...)
+		data = data[i+1:]
+	}
+	return n, err
+}
+
+// line returns the line numbered n, or nil when it has been discarded or not
+// read yet.
+func (lb *lineBuffer) line(n int) []byte {
+	if n < lb.first || n >= lb.first+len(lb.lines) {
+		return nil
+	}
+	return lb.lines[n-lb.first]
+}
+
+// discard forgets the lines before the line numbered n.
+func (lb *lineBuffer) discard(n int) {
+	if d := n - lb.first; d > 0 {
+		if d > len(lb.lines) {
+			d = len(lb.lines)
+		}
+		for i := 0; i < d; i++ {
+			lb.lines[i] = nil
+		}
+		lb.lines = lb.lines[d:]
+		lb.first += d
+	}
+}
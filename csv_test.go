@@ -0,0 +1,52 @@
+package kanaco
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCSVTransformer(t *testing.T) {
+	data := []struct {
+		in     string
+		expect string
+	}{
+		{"ｶﾅ,０３,ｶﾅ\n", "カナ,03,ｶﾅ\n"},
+		{"\"ｶﾅ\",\"０３\",ｶﾅ\r\n\r\nｱ,\"１\r\n２\",\"a\"\"b\"\r\n", "\"カナ\",\"03\",ｶﾅ\r\n\r\nア,\"1\r\n2\",\"a\"\"b\"\r\n"},
+		{"ｱ,１，２\nｲ", "ア,\"1,2\"\nイ"},
+		{"ｱ\n\n", "ア\n\n"},
+		{"", ""},
+		{"ｱ, b,c\n", "ア, b,c\n"},
+		{"\"a\",\"ａ\",\"ｶﾅ\"\n", "\"a\",\"a\",\"ｶﾅ\"\n"},
+		{"ｱ,\"b\"\"\",\"\"\n", "ア,\"b\"\"\",\"\"\n"},
+		{"ｱ, １\n", "ア, 1\n"},
+	}
+	tr := NewCSVTransformer(',')
+	tr.SetColumn(0, "KV")
+	tr.SetColumn(1, "a")
+	for _, v := range data {
+		buf := &bytes.Buffer{}
+		if err := tr.Transform(buf, strings.NewReader(v.in)); err != nil || buf.String() != v.expect {
+			t.Errorf("[%q] expect %q, result %q %v", v.in, v.expect, buf.String(), err)
+		}
+	}
+
+	tr = NewCSVTransformer('\t')
+	tr.SetColumnName("kana", "KV")
+	tr.SetColumnName("phone", "a")
+	in := "\ufeffkana\tname\tphone\nﾔﾏﾀﾞ\t山田\t０３－１２３４\n"
+	expect := "\ufeffkana\tname\tphone\nヤマダ\t山田\t03-1234\n"
+	buf := &bytes.Buffer{}
+	if err := tr.Transform(buf, strings.NewReader(in)); err != nil || buf.String() != expect {
+		t.Errorf("expect %q, result %q %v", expect, buf.String(), err)
+	}
+
+	tr.SetColumnName("none", "KV")
+	if err := tr.Transform(&bytes.Buffer{}, strings.NewReader(in)); !errors.Is(err, ErrCSVColumn) {
+		t.Errorf("expect %v, result %v", ErrCSVColumn, err)
+	}
+	if err := NewCSVTransformer(',').Transform(&bytes.Buffer{}, strings.NewReader("\"a\n")); err == nil {
+		t.Errorf("expect a parse error")
+	}
+}
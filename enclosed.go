@@ -0,0 +1,104 @@
+package kanaco
+
+import "strings"
+
+// enclosed maps enclosed alphanumerics, ideographs and kana to their
+// compatibility decomposition.
+var enclosed = map[string]string{
+	"①": "1", "②": "2", "③": "3", "④": "4", "⑤": "5", "⑥": "6", "⑦": "7", "⑧": "8",
+	"⑨": "9", "⑩": "10", "⑪": "11", "⑫": "12", "⑬": "13", "⑭": "14", "⑮": "15", "⑯": "16",
+	"⑰": "17", "⑱": "18", "⑲": "19", "⑳": "20", "⑴": "(1)", "⑵": "(2)", "⑶": "(3)", "⑷": "(4)",
+	"⑸": "(5)", "⑹": "(6)", "⑺": "(7)", "⑻": "(8)", "⑼": "(9)", "⑽": "(10)", "⑾": "(11)", "⑿": "(12)",
+	"⒀": "(13)", "⒁": "(14)", "⒂": "(15)", "⒃": "(16)", "⒄": "(17)", "⒅": "(18)", "⒆": "(19)", "⒇": "(20)",
+	"⒈": "1.", "⒉": "2.", "⒊": "3.", "⒋": "4.", "⒌": "5.", "⒍": "6.", "⒎": "7.", "⒏": "8.",
+	"⒐": "9.", "⒑": "10.", "⒒": "11.", "⒓": "12.", "⒔": "13.", "⒕": "14.", "⒖": "15.", "⒗": "16.",
+	"⒘": "17.", "⒙": "18.", "⒚": "19.", "⒛": "20.", "⒜": "(a)", "⒝": "(b)", "⒞": "(c)", "⒟": "(d)",
+	"⒠": "(e)", "⒡": "(f)", "⒢": "(g)", "⒣": "(h)", "⒤": "(i)", "⒥": "(j)", "⒦": "(k)", "⒧": "(l)",
+	"⒨": "(m)", "⒩": "(n)", "⒪": "(o)", "⒫": "(p)", "⒬": "(q)", "⒭": "(r)", "⒮": "(s)", "⒯": "(t)",
+	"⒰": "(u)", "⒱": "(v)", "⒲": "(w)", "⒳": "(x)", "⒴": "(y)", "⒵": "(z)", "Ⓐ": "A", "Ⓑ": "B",
+	"Ⓒ": "C", "Ⓓ": "D", "Ⓔ": "E", "Ⓕ": "F", "Ⓖ": "G", "Ⓗ": "H", "Ⓘ": "I", "Ⓙ": "J",
+	"Ⓚ": "K", "Ⓛ": "L", "Ⓜ": "M", "Ⓝ": "N", "Ⓞ": "O", "Ⓟ": "P", "Ⓠ": "Q", "Ⓡ": "R",
+	"Ⓢ": "S", "Ⓣ": "T", "Ⓤ": "U", "Ⓥ": "V", "Ⓦ": "W", "Ⓧ": "X", "Ⓨ": "Y", "Ⓩ": "Z",
+	"ⓐ": "a", "ⓑ": "b", "ⓒ": "c", "ⓓ": "d", "ⓔ": "e", "ⓕ": "f", "ⓖ": "g", "ⓗ": "h",
+	"ⓘ": "i", "ⓙ": "j", "ⓚ": "k", "ⓛ": "l", "ⓜ": "m", "ⓝ": "n", "ⓞ": "o", "ⓟ": "p",
+	"ⓠ": "q", "ⓡ": "r", "ⓢ": "s", "ⓣ": "t", "ⓤ": "u", "ⓥ": "v", "ⓦ": "w", "ⓧ": "x",
+	"ⓨ": "y", "ⓩ": "z", "⓪": "0", "⓫": "11", "⓬": "12", "⓭": "13", "⓮": "14", "⓯": "15",
+	"⓰": "16", "⓱": "17", "⓲": "18", "⓳": "19", "⓴": "20", "⓵": "1", "⓶": "2", "⓷": "3",
+	"⓸": "4", "⓹": "5", "⓺": "6", "⓻": "7", "⓼": "8", "⓽": "9", "⓾": "10", "⓿": "0",
+	"❶": "1", "❷": "2", "❸": "3", "❹": "4", "❺": "5", "❻": "6", "❼": "7", "❽": "8",
+	"❾": "9", "❿": "10", "➀": "1", "➁": "2", "➂": "3", "➃": "4", "➄": "5", "➅": "6",
+	"➆": "7", "➇": "8", "➈": "9", "➉": "10", "➊": "1", "➋": "2", "➌": "3", "➍": "4",
+	"➎": "5", "➏": "6", "➐": "7", "➑": "8", "➒": "9", "➓": "10", "㈠": "(一)", "㈡": "(二)",
+	"㈢": "(三)", "㈣": "(四)", "㈤": "(五)", "㈥": "(六)", "㈦": "(七)", "㈧": "(八)", "㈨": "(九)", "㈩": "(十)",
+	"㈪": "(月)", "㈫": "(火)", "㈬": "(水)", "㈭": "(木)", "㈮": "(金)", "㈯": "(土)", "㈰": "(日)", "㈱": "(株)",
+	"㈲": "(有)", "㈳": "(社)", "㈴": "(名)", "㈵": "(特)", "㈶": "(財)", "㈷": "(祝)", "㈸": "(労)", "㈹": "(代)",
+	"㈺": "(呼)", "㈻": "(学)", "㈼": "(監)", "㈽": "(企)", "㈾": "(資)", "㈿": "(協)", "㉀": "(祭)", "㉁": "(休)",
+	"㉂": "(自)", "㉃": "(至)", "㉄": "問", "㉅": "幼", "㉆": "文", "㉇": "箏", "㉐": "PTE", "㉑": "21",
+	"㉒": "22", "㉓": "23", "㉔": "24", "㉕": "25", "㉖": "26", "㉗": "27", "㉘": "28", "㉙": "29",
+	"㉚": "30", "㉛": "31", "㉜": "32", "㉝": "33", "㉞": "34", "㉟": "35", "㊀": "一", "㊁": "二",
+	"㊂": "三", "㊃": "四", "㊄": "五", "㊅": "六", "㊆": "七", "㊇": "八", "㊈": "九", "㊉": "十",
+	"㊊": "月", "㊋": "火", "㊌": "水", "㊍": "木", "㊎": "金", "㊏": "土", "㊐": "日", "㊑": "株",
+	"㊒": "有", "㊓": "社", "㊔": "名", "㊕": "特", "㊖": "財", "㊗": "祝", "㊘": "労", "㊙": "秘",
+	"㊚": "男", "㊛": "女", "㊜": "適", "㊝": "優", "㊞": "印", "㊟": "注", "㊠": "項", "㊡": "休",
+	"㊢": "写", "㊣": "正", "㊤": "上", "㊥": "中", "㊦": "下", "㊧": "左", "㊨": "右", "㊩": "医",
+	"㊪": "宗", "㊫": "学", "㊬": "監", "㊭": "企", "㊮": "資", "㊯": "協", "㊰": "夜", "㊱": "36",
+	"㊲": "37", "㊳": "38", "㊴": "39", "㊵": "40", "㊶": "41", "㊷": "42", "㊸": "43", "㊹": "44",
+	"㊺": "45", "㊻": "46", "㊼": "47", "㊽": "48", "㊾": "49", "㊿": "50", "㋀": "1月", "㋁": "2月",
+	"㋂": "3月", "㋃": "4月", "㋄": "5月", "㋅": "6月", "㋆": "7月", "㋇": "8月", "㋈": "9月", "㋉": "10月",
+	"㋊": "11月", "㋋": "12月", "㋌": "Hg", "㋍": "erg", "㋎": "eV", "㋏": "LTD", "㋐": "ア", "㋑": "イ",
+	"㋒": "ウ", "㋓": "エ", "㋔": "オ", "㋕": "カ", "㋖": "キ", "㋗": "ク", "㋘": "ケ", "㋙": "コ",
+	"㋚": "サ", "㋛": "シ", "㋜": "ス", "㋝": "セ", "㋞": "ソ", "㋟": "タ", "㋠": "チ", "㋡": "ツ",
+	"㋢": "テ", "㋣": "ト", "㋤": "ナ", "㋥": "ニ", "㋦": "ヌ", "㋧": "ネ", "㋨": "ノ", "㋩": "ハ",
+	"㋪": "ヒ", "㋫": "フ", "㋬": "ヘ", "㋭": "ホ", "㋮": "マ", "㋯": "ミ", "㋰": "ム", "㋱": "メ",
+	"㋲": "モ", "㋳": "ヤ", "㋴": "ユ", "㋵": "ヨ", "㋶": "ラ", "㋷": "リ", "㋸": "ル", "㋹": "レ",
+	"㋺": "ロ", "㋻": "ワ", "㋼": "ヰ", "㋽": "ヱ", "㋾": "ヲ", "🄀": "0.", "🄁": "0,", "🄂": "1,",
+	"🄃": "2,", "🄄": "3,", "🄅": "4,", "🄆": "5,", "🄇": "6,", "🄈": "7,", "🄉": "8,", "🄊": "9,",
+	"🄋": "0", "🄌": "0", "🄐": "(A)", "🄑": "(B)", "🄒": "(C)", "🄓": "(D)", "🄔": "(E)", "🄕": "(F)",
+	"🄖": "(G)", "🄗": "(H)", "🄘": "(I)", "🄙": "(J)", "🄚": "(K)", "🄛": "(L)", "🄜": "(M)", "🄝": "(N)",
+	"🄞": "(O)", "🄟": "(P)", "🄠": "(Q)", "🄡": "(R)", "🄢": "(S)", "🄣": "(T)", "🄤": "(U)", "🄥": "(V)",
+	"🄦": "(W)", "🄧": "(X)", "🄨": "(Y)", "🄩": "(Z)", "🄪": "〔S〕", "🄫": "C", "🄬": "R", "🄭": "CD",
+	"🄮": "WZ", "🄰": "A", "🄱": "B", "🄲": "C", "🄳": "D", "🄴": "E", "🄵": "F", "🄶": "G",
+	"🄷": "H", "🄸": "I", "🄹": "J", "🄺": "K", "🄻": "L", "🄼": "M", "🄽": "N", "🄾": "O",
+	"🄿": "P", "🅀": "Q", "🅁": "R", "🅂": "S", "🅃": "T", "🅄": "U", "🅅": "V", "🅆": "W",
+	"🅇": "X", "🅈": "Y", "🅉": "Z", "🅊": "HV", "🅋": "MV", "🅌": "SD", "🅍": "SS", "🅎": "PPV",
+	"🅏": "WC", "🅐": "A", "🅑": "B", "🅒": "C", "🅓": "D", "🅔": "E", "🅕": "F", "🅖": "G",
+	"🅗": "H", "🅘": "I", "🅙": "J", "🅚": "K", "🅛": "L", "🅜": "M", "🅝": "N", "🅞": "O",
+	"🅟": "P", "🅠": "Q", "🅡": "R", "🅢": "S", "🅣": "T", "🅤": "U", "🅥": "V", "🅦": "W",
+	"🅧": "X", "🅨": "Y", "🅩": "Z", "🅪": "MC", "🅫": "MD", "🅬": "MR", "🅰": "A", "🅱": "B",
+	"🅲": "C", "🅳": "D", "🅴": "E", "🅵": "F", "🅶": "G", "🅷": "H", "🅸": "I", "🅹": "J",
+	"🅺": "K", "🅻": "L", "🅼": "M", "🅽": "N", "🅾": "O", "🅿": "P", "🆀": "Q", "🆁": "R",
+	"🆂": "S", "🆃": "T", "🆄": "U", "🆅": "V", "🆆": "W", "🆇": "X", "🆈": "Y", "🆉": "Z",
+	"🆊": "P", "🆐": "DJ", "🈐": "手", "🈑": "字", "🈒": "双", "🈓": "デ", "🈔": "二", "🈕": "多",
+	"🈖": "解", "🈗": "天", "🈘": "交", "🈙": "映", "🈚": "無", "🈛": "料", "🈜": "前", "🈝": "後",
+	"🈞": "再", "🈟": "新", "🈠": "初", "🈡": "終", "🈢": "生", "🈣": "販", "🈤": "声", "🈥": "吹",
+	"🈦": "演", "🈧": "投", "🈨": "捕", "🈩": "一", "🈪": "三", "🈫": "遊", "🈬": "左", "🈭": "中",
+	"🈮": "右", "🈯": "指", "🈰": "走", "🈱": "打", "🈲": "禁", "🈳": "空", "🈴": "合", "🈵": "満",
+	"🈶": "有", "🈷": "月", "🈸": "申", "🈹": "割", "🈺": "営", "🈻": "配", "🉀": "〔本〕", "🉁": "〔三〕",
+	"🉂": "〔二〕", "🉃": "〔安〕", "🉄": "〔点〕", "🉅": "〔打〕", "🉆": "〔盗〕", "🉇": "〔勝〕", "🉈": "〔敗〕", "🉐": "得",
+	"🉑": "可",
+}
+
+func lowerE(c *character) {
+	if c.filters&FLT_LOWER_E != FLT_LOWER_E {
+		return
+	}
+	v, ok := enclosed[string(c.val)]
+	if !ok {
+		return
+	}
+	if !c.cv.paren {
+		v = unparen(v)
+	}
+	c.cval = []byte(v)
+	c.refeed = true
+}
+
+// unparen drops the brackets around "(1)", "(株)" and "〔本〕".
+func unparen(s string) string {
+	for _, p := range [][2]string{{"(", ")"}, {"〔", "〕"}} {
+		if strings.HasPrefix(s, p[0]) && strings.HasSuffix(s, p[1]) {
+			return s[len(p[0]) : len(s)-len(p[1])]
+		}
+	}
+	return s
+}
@@ -0,0 +1,31 @@
+package kanaco
+
+import "testing"
+
+func TestEnclosed(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+		opts             []Option
+	}{
+		{"⑫番", "e", "12番", nil},
+		{"⑫番", "en", "12番", nil},
+		{"⑫番", "eN", "１２番", nil},
+		{"⑫番", "Ne", "１２番", nil},
+		{"⒈⒛", "e", "1.20.", nil},
+		{"Ⓐⓩ⓿❿", "eR", "Ａｚ010", nil},
+		{"⑴㈱〔本〕", "e", "1株〔本〕", nil},
+		{"⑴㈱🉀", "e", "1株本", nil},
+		{"⑴㈱🉀", "e", "(1)(株)〔本〕", []Option{KeepParentheses()}},
+		{"⑴㈱", "eA", "（１）（株）", []Option{KeepParentheses()}},
+		{"㋐㋕㋾", "e", "アカヲ", nil},
+		{"㋐㋕㋾", "ek", "ｱｶｦ", nil},
+		{"㊤㊥㊦", "e", "上中下", nil},
+		{"①", "n", "①", nil},
+	}
+	for _, tt := range tests {
+		result := NewConverter(tt.mode, tt.opts...).String(tt.in)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
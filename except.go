@@ -0,0 +1,51 @@
+package kanaco
+
+// Except leaves the characters of chars as they are whatever the mode, e.g.
+// Except("@-") with the A mode keeps @ and - in ASCII. A hankaku kana and
+// its voiced sound mark (ｶﾞ) are a single character.
+func Except(chars string) Option {
+	return func(cv *Converter) {
+		if cv.except == nil {
+			cv.except = map[string]bool{}
+		}
+		addChars(cv.except, chars)
+	}
+}
+
+// Only converts the characters of chars and leaves the others as they are,
+// e.g. Only("・ー") with the k mode converts just ・ and ー.
+func Only(chars string) Option {
+	return func(cv *Converter) {
+		if cv.only == nil {
+			cv.only = map[string]bool{}
+		}
+		addChars(cv.only, chars)
+	}
+}
+
+func addChars(set map[string]bool, chars string) {
+	for i := 0; i < len(chars); {
+		_, n := classify(chars[i:])
+		set[chars[i:i+n]] = true
+		i += n
+	}
+}
+
+// excluded reports whether val, which may be several characters such as a
+// run of digits, is left as it is. It is when any of the characters is in
+// Except or any is not in Only.
+func (cv *Converter) excluded(val []byte) bool {
+	if cv.except == nil && cv.only == nil {
+		return false
+	}
+	s := string(val)
+	for i := 0; i < len(s); {
+		_, n := classify(s[i:])
+		ch := s[i : i+n]
+		if cv.except[ch] || (cv.only != nil && !cv.only[ch]) {
+			return true
+		}
+		i += n
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package kanaco
+
+import (
+	"testing"
+)
+
+func TestExceptOnly(t *testing.T) {
+	data := []struct {
+		mode   string
+		opts   []Option
+		in     string
+		expect string
+	}{
+		{"A", []Option{Except("@-")}, "user@example-1", "ｕｓｅｒ@ｅｘａｍｐｌｅ-１"},
+		{"k", []Option{Except("・ー")}, "カード・ケース", "ｶーﾄﾞ・ｹーｽ"},
+		{"k", []Option{Only("・ー")}, "カード・ケース", "カｰド･ケｰス"},
+		{"KV", []Option{Except("ｶﾞ")}, "ｶﾞｶｶﾞ", "ｶﾞカｶﾞ"},
+		{"KV", []Option{Except("ｶ")}, "ｶﾞｶ", "ガｶ"},
+		{"A", []Option{Except("@"), Except("-")}, "@-a", "@-ａ"},
+		{"A", []Option{Only("abc"), Except("b")}, "abcd", "ａbｃd"},
+		{"J", []Option{Except("1")}, "12 34", "12 三十四"},
+		{"A", []Option{Only("")}, "abc", "abc"},
+	}
+	for _, v := range data {
+		if result := NewConverter(v.mode, v.opts...).String(v.in); result != v.expect {
+			t.Errorf("[%s] expect %s, result %s", v.in, v.expect, result)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package kanaco
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+type FoldOptions int
+
+const (
+	FoldWidth  FoldOptions = 1 << iota // ａ = a, ｶﾞ = ガ, U+3000 = U+0020
+	FoldKana                           // あ = ア
+	FoldSmall                          // ぁ = あ, ッ = ツ, ヵ = カ
+	FoldVoiced                         // が = か, ぱ = は; ゛ and ゜ are ignored
+	FoldCase                           // A = a
+	FoldAll    = FoldWidth | FoldKana | FoldSmall | FoldVoiced | FoldCase
+)
+
+// halfwidth is the zenkaku form of U+FF61 - U+FF9F.
+const halfwidth = "。「」、・ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン゛゜"
+
+// narrow tags the folded key of a hankaku character when widths are not
+// folded, so that ｶ and カ differ while still sharing the other foldings.
+const narrow rune = 1 << 21
+
+var (
+	halfwidthRunes = []rune(halfwidth)
+	smallKana      = map[rune]rune{
+		'ぁ': 'あ', 'ぃ': 'い', 'ぅ': 'う', 'ぇ': 'え', 'ぉ': 'お', 'っ': 'つ',
+		'ゃ': 'や', 'ゅ': 'ゆ', 'ょ': 'よ', 'ゎ': 'わ', 'ゕ': 'か', 'ゖ': 'け',
+		'ァ': 'ア', 'ィ': 'イ', 'ゥ': 'ウ', 'ェ': 'エ', 'ォ': 'オ', 'ッ': 'ツ',
+		'ャ': 'ヤ', 'ュ': 'ユ', 'ョ': 'ヨ', 'ヮ': 'ワ', 'ヵ': 'カ', 'ヶ': 'ケ',
+	}
+	voicedKana = map[rune]rune{}
+)
+
+func init() {
+	voiced := []rune("がぎぐげござじずぜぞだぢづでどばびぶべぼぱぴぷぺぽゔゞガギグゲゴザジズゼゾダヂヅデドバビブベボパピプペポヴヷヸヹヺヾ")
+	plain := []rune("かきくけこさしすせそたちつてとはひふへほはひふへほうゝカキクケコサシスセソタチツテトハヒフヘホハヒフヘホウワヰヱヲヽ")
+	for i, r := range voiced {
+		voicedKana[r] = plain[i]
+	}
+}
+
+// EqualFold reports whether a and b are equal under the given foldings.
+func EqualFold(a, b string, fold FoldOptions) bool {
+	return Compare(a, b, fold) == 0
+}
+
+// Compare compares a and b character by character after folding them and
+// returns -1, 0 or +1.
+func Compare(a, b string, fold FoldOptions) int {
+	i, j := 0, 0
+	for {
+		ra, ni := nextKey(a, i, fold)
+		rb, nj := nextKey(b, j, fold)
+		if ra < 0 || rb < 0 {
+			if ra == rb {
+				return 0
+			} else if ra < 0 {
+				return -1
+			}
+			return 1
+		}
+		if ra != rb {
+			if ra < rb {
+				return -1
+			}
+			return 1
+		}
+		i, j = ni, nj
+	}
+}
+
+// HasPrefix reports whether s begins with prefix under the given foldings.
+func HasPrefix(s, prefix string, fold FoldOptions) bool {
+	_, ok := matchFold(s, prefix, fold)
+	return ok
+}
+
+// Index returns the byte offset in s of the first match of substr under the
+// given foldings, or -1.
+func Index(s, substr string, fold FoldOptions) int {
+	if r, _ := nextKey(substr, 0, fold); r < 0 {
+		return 0
+	}
+	for i := 0; i < len(s); {
+		r, n := foldRune(s[i:], fold)
+		if r >= 0 {
+			if _, ok := matchFold(s[i:], substr, fold); ok {
+				return i
+			}
+		}
+		i += n
+	}
+	return -1
+}
+
+// Contains reports whether substr is within s under the given foldings.
+func Contains(s, substr string, fold FoldOptions) bool {
+	return Index(s, substr, fold) >= 0
+}
+
+// matchFold reports whether s begins with prefix and returns the length of
+// the match in s.
+func matchFold(s, prefix string, fold FoldOptions) (int, bool) {
+	i, j := 0, 0
+	for {
+		rp, nj := nextKey(prefix, j, fold)
+		if rp < 0 {
+			return i, true
+		}
+		rs, ni := nextKey(s, i, fold)
+		if rs != rp {
+			return 0, false
+		}
+		i, j = ni, nj
+	}
+}
+
+// nextKey returns the folded key of the first character of s[i:] that is not
+// ignored, and the offset just after it. The key is -1 at the end of s.
+func nextKey(s string, i int, fold FoldOptions) (rune, int) {
+	for i < len(s) {
+		r, n := foldRune(s[i:], fold)
+		i += n
+		if r >= 0 {
+			return r, i
+		}
+	}
+	return -1, i
+}
+
+// foldRune returns the folded key of the character at the head of s and its
+// length in bytes. A hankaku kana followed by ﾞ or ﾟ is a single character.
+// The key is -1 when the character is ignored by the foldings.
+func foldRune(s string, fold FoldOptions) (rune, int) {
+	r, n := utf8.DecodeRuneInString(s)
+	wide := fold&FoldWidth == FoldWidth
+	tag := rune(0)
+	switch {
+	case r >= 0xff01 && r <= 0xff5e && wide: // ！ - ～
+		r -= 0xfee0
+	case r == 0x3000 && wide: // Space
+		r = 0x20
+	case r >= 0xff61 && r <= 0xff9f: // ｡ - ﾟ
+		r = halfwidthRunes[r-0xff61]
+		if m, size := utf8.DecodeRuneInString(s[n:]); m == 0xff9e || m == 0xff9f {
+			if v := voice(r, m == 0xff9f); v != r {
+				r = v
+				n += size
+			}
+		}
+		if !wide {
+			tag = narrow
+		}
+	}
+	if fold&FoldVoiced == FoldVoiced {
+		switch r {
+		case 0x3099, 0x309a, 0x309b, 0x309c: // combining and spacing ゛ ゜
+			return -1, n
+		}
+		if v, ok := voicedKana[r]; ok {
+			r = v
+		}
+	}
+	if fold&FoldSmall == FoldSmall {
+		if v, ok := smallKana[r]; ok {
+			r = v
+		}
+	}
+	if fold&FoldKana == FoldKana {
+		if (r >= 0x3041 && r <= 0x3096) || (r >= 0x309d && r <= 0x309e) { // ぁ - ゖ, ゝゞ
+			r += 0x60
+		}
+	}
+	if fold&FoldCase == FoldCase {
+		r = unicode.ToLower(r)
+	}
+	return r | tag, n
+}
+
+// voice returns the voiced (or semi-voiced) form of a zenkaku katakana, or r
+// itself when there is none.
+func voice(r rune, semi bool) rune {
+	switch {
+	case r == 'ウ' && !semi:
+		return 'ヴ'
+	case r >= 'ハ' && r <= 'ホ' && (r-'ハ')%3 == 0:
+		if semi {
+			return r + 2
+		}
+		return r + 1
+	case semi:
+		return r
+	case r >= 'カ' && r <= 'ヂ' && (r-'カ')%2 == 0:
+		return r + 1
+	case r >= 'ツ' && r <= 'ド' && (r-'ツ')%2 == 0:
+		return r + 1
+	}
+	return r
+}
@@ -0,0 +1,88 @@
+package kanaco
+
+import "testing"
+
+func TestEqualFold(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		fold   FoldOptions
+		expect bool
+	}{
+		{"ガス", "ｶﾞｽ", FoldWidth, true},
+		{"ガス", "ｶﾞｽ", FoldKana, false},
+		{"がす", "ｶﾞｽ", FoldWidth | FoldKana, true},
+		{"がす", "ｶﾞｽ", FoldKana, false},
+		{"がす", "ｶﾞス", FoldKana, false},
+		{"ｶﾞｽ", "ｶﾞｽ", 0, true},
+		{"ｶﾞｽ", "ｶｽ", FoldVoiced, true},
+		{"ｶﾞｽ", "カス", FoldVoiced, false},
+		{"か゛す", "がす", FoldVoiced, true},
+		{"キャッシュ", "キヤツシユ", FoldSmall, true},
+		{"ｷｬｯｼｭ", "きやつしゆ", FoldAll, true},
+		{"ＡＢＣ　abc", "abc ABC", FoldWidth | FoldCase, true},
+		{"ＡＢＣ", "ａｂｃ", FoldCase, true},
+		{"ＡＢＣ", "abc", FoldCase, false},
+		{"ヴァイオリン", "ばいおりん", FoldAll, false},
+		{"ヴァイオリン", "うあいおりん", FoldAll, true},
+		{"パン", "はん", FoldKana | FoldVoiced, true},
+		{"ゞ", "ヽ", FoldKana | FoldVoiced, true},
+		{"あい", "あいう", FoldAll, false},
+	}
+	for _, tt := range tests {
+		if result := EqualFold(tt.a, tt.b, tt.fold); result != tt.expect {
+			t.Errorf("%s, %s (%d): expect %v, result %v", tt.a, tt.b, tt.fold, tt.expect, result)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{"あ", "ｱ", 0},
+		{"あ", "ｲ", -1},
+		{"いう", "ｱ", 1},
+		{"あ", "ｱｲ", -1},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		if result := Compare(tt.a, tt.b, FoldAll); result != tt.expect {
+			t.Errorf("%s, %s: expect %d, result %d", tt.a, tt.b, tt.expect, result)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		s, substr string
+		expect    int
+	}{
+		{"東京ｶﾞｽ株式会社", "がす", 6},
+		{"東京ｶﾞｽ株式会社", "ガス株", 6},
+		{"東京ｶﾞｽ株式会社", "かす", 6},
+		{"東京ｶﾞｽ", "ﾞｽ", 12},
+		{"ABCＤＥＦ", "cd", 2},
+		{"ABC", "", 0},
+		{"", "", 0},
+		{"", "a", -1},
+		{"あいう", "え", -1},
+	}
+	for _, tt := range tests {
+		if result := Index(tt.s, tt.substr, FoldAll); result != tt.expect {
+			t.Errorf("%s, %s: expect %d, result %d", tt.s, tt.substr, tt.expect, result)
+		}
+	}
+	if !Contains("ﾃﾞｰﾀﾍﾞｰｽ", "データ", FoldWidth) {
+		t.Error("Contains: expect true")
+	}
+	if Contains("ﾃﾞｰﾀﾍﾞｰｽ", "でーた", FoldWidth) {
+		t.Error("Contains: expect false")
+	}
+	if !HasPrefix("ﾃﾞｰﾀﾍﾞｰｽ", "でーた", FoldWidth|FoldKana) {
+		t.Error("HasPrefix: expect true")
+	}
+	if HasPrefix("ﾃﾞｰﾀ", "でーたべーす", FoldAll) {
+		t.Error("HasPrefix: expect false")
+	}
+}
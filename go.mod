@@ -1,3 +1,5 @@
 module github.com/elfincafe/kanaco
 
 go 1.18
+
+require golang.org/x/text v0.22.0
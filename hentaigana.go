@@ -0,0 +1,82 @@
+package kanaco
+
+// hentaigana maps the hentaigana and archaic kana of the Kana Supplement and
+// Kana Extended-A blocks to modern kana.
+var hentaigana = map[string]string{
+	"𛀀": "エ", "𛀁": "え", "𛀂": "あ", "𛀃": "あ", "𛀄": "あ", "𛀅": "あ", "𛀆": "い", "𛀇": "い",
+	"𛀈": "い", "𛀉": "い", "𛀊": "う", "𛀋": "う", "𛀌": "う", "𛀍": "う", "𛀎": "う", "𛀏": "え",
+	"𛀐": "え", "𛀑": "え", "𛀒": "え", "𛀓": "え", "𛀔": "お", "𛀕": "お", "𛀖": "お", "𛀗": "か",
+	"𛀘": "か", "𛀙": "か", "𛀚": "か", "𛀛": "か", "𛀜": "か", "𛀝": "か", "𛀞": "か", "𛀟": "か",
+	"𛀠": "か", "𛀡": "か", "𛀢": "か", "𛀣": "き", "𛀤": "き", "𛀥": "き", "𛀦": "き", "𛀧": "き",
+	"𛀨": "き", "𛀩": "き", "𛀪": "き", "𛀫": "く", "𛀬": "く", "𛀭": "く", "𛀮": "く", "𛀯": "く",
+	"𛀰": "く", "𛀱": "く", "𛀲": "け", "𛀳": "け", "𛀴": "け", "𛀵": "け", "𛀶": "け", "𛀷": "け",
+	"𛀸": "こ", "𛀹": "こ", "𛀺": "こ", "𛀻": "こ", "𛀼": "さ", "𛀽": "さ", "𛀾": "さ", "𛀿": "さ",
+	"𛁀": "さ", "𛁁": "さ", "𛁂": "さ", "𛁃": "さ", "𛁄": "し", "𛁅": "し", "𛁆": "し", "𛁇": "し",
+	"𛁈": "し", "𛁉": "し", "𛁊": "す", "𛁋": "す", "𛁌": "す", "𛁍": "す", "𛁎": "す", "𛁏": "す",
+	"𛁐": "す", "𛁑": "す", "𛁒": "せ", "𛁓": "せ", "𛁔": "せ", "𛁕": "せ", "𛁖": "せ", "𛁗": "そ",
+	"𛁘": "そ", "𛁙": "そ", "𛁚": "そ", "𛁛": "そ", "𛁜": "そ", "𛁝": "そ", "𛁞": "た", "𛁟": "た",
+	"𛁠": "た", "𛁡": "た", "𛁢": "ち", "𛁣": "ち", "𛁤": "ち", "𛁥": "ち", "𛁦": "ち", "𛁧": "ち",
+	"𛁨": "ち", "𛁩": "つ", "𛁪": "つ", "𛁫": "つ", "𛁬": "つ", "𛁭": "つ", "𛁮": "て", "𛁯": "て",
+	"𛁰": "て", "𛁱": "て", "𛁲": "て", "𛁳": "て", "𛁴": "て", "𛁵": "て", "𛁶": "て", "𛁷": "と",
+	"𛁸": "と", "𛁹": "と", "𛁺": "と", "𛁻": "と", "𛁼": "と", "𛁽": "と", "𛁾": "な", "𛁿": "な",
+	"𛂀": "な", "𛂁": "な", "𛂂": "な", "𛂃": "な", "𛂄": "な", "𛂅": "な", "𛂆": "な", "𛂇": "に",
+	"𛂈": "に", "𛂉": "に", "𛂊": "に", "𛂋": "に", "𛂌": "に", "𛂍": "に", "𛂎": "に", "𛂏": "ぬ",
+	"𛂐": "ぬ", "𛂑": "ぬ", "𛂒": "ね", "𛂓": "ね", "𛂔": "ね", "𛂕": "ね", "𛂖": "ね", "𛂗": "ね",
+	"𛂘": "ね", "𛂙": "の", "𛂚": "の", "𛂛": "の", "𛂜": "の", "𛂝": "の", "𛂞": "は", "𛂟": "は",
+	"𛂠": "は", "𛂡": "は", "𛂢": "は", "𛂣": "は", "𛂤": "は", "𛂥": "は", "𛂦": "は", "𛂧": "は",
+	"𛂨": "は", "𛂩": "ひ", "𛂪": "ひ", "𛂫": "ひ", "𛂬": "ひ", "𛂭": "ひ", "𛂮": "ひ", "𛂯": "ひ",
+	"𛂰": "ふ", "𛂱": "ふ", "𛂲": "ふ", "𛂳": "へ", "𛂴": "へ", "𛂵": "へ", "𛂶": "へ", "𛂷": "へ",
+	"𛂸": "へ", "𛂹": "へ", "𛂺": "ほ", "𛂻": "ほ", "𛂼": "ほ", "𛂽": "ほ", "𛂾": "ほ", "𛂿": "ほ",
+	"𛃀": "ほ", "𛃁": "ほ", "𛃂": "ま", "𛃃": "ま", "𛃄": "ま", "𛃅": "ま", "𛃆": "ま", "𛃇": "ま",
+	"𛃈": "ま", "𛃉": "み", "𛃊": "み", "𛃋": "み", "𛃌": "み", "𛃍": "み", "𛃎": "み", "𛃏": "み",
+	"𛃐": "む", "𛃑": "む", "𛃒": "む", "𛃓": "む", "𛃔": "め", "𛃕": "め", "𛃖": "め", "𛃗": "も",
+	"𛃘": "も", "𛃙": "も", "𛃚": "も", "𛃛": "も", "𛃜": "も", "𛃝": "や", "𛃞": "や", "𛃟": "や",
+	"𛃠": "や", "𛃡": "や", "𛃢": "や", "𛃣": "ゆ", "𛃤": "ゆ", "𛃥": "ゆ", "𛃦": "ゆ", "𛃧": "よ",
+	"𛃨": "よ", "𛃩": "よ", "𛃪": "よ", "𛃫": "よ", "𛃬": "よ", "𛃭": "ら", "𛃮": "ら", "𛃯": "ら",
+	"𛃰": "ら", "𛃱": "り", "𛃲": "り", "𛃳": "り", "𛃴": "り", "𛃵": "り", "𛃶": "り", "𛃷": "り",
+	"𛃸": "る", "𛃹": "る", "𛃺": "る", "𛃻": "る", "𛃼": "る", "𛃽": "る", "𛃾": "れ", "𛃿": "れ",
+	"𛄀": "れ", "𛄁": "れ", "𛄂": "ろ", "𛄃": "ろ", "𛄄": "ろ", "𛄅": "ろ", "𛄆": "ろ", "𛄇": "ろ",
+	"𛄈": "わ", "𛄉": "わ", "𛄊": "わ", "𛄋": "わ", "𛄌": "わ", "𛄍": "ゐ", "𛄎": "ゐ", "𛄏": "ゐ",
+	"𛄐": "ゐ", "𛄑": "ゐ", "𛄒": "ゑ", "𛄓": "ゑ", "𛄔": "ゑ", "𛄕": "ゑ", "𛄖": "を", "𛄗": "を",
+	"𛄘": "を", "𛄙": "を", "𛄚": "を", "𛄛": "を", "𛄜": "を", "𛄝": "ん", "𛄞": "ん", "𛄟": "う",
+	"𛄠": "イ", "𛄡": "エ", "𛄢": "ウ", "𛅐": "ゐ", "𛅑": "ゑ", "𛅒": "を", "𛅤": "ヰ", "𛅥": "ヱ",
+	"𛅦": "ヲ", "𛅧": "ン",
+}
+
+var archaic = map[string]string{
+	"ゐ": "い", "ゑ": "え", "ヰ": "イ", "ヱ": "エ",
+}
+
+// FoldArchaic makes the g mode also convert ゐ ゑ ヰ ヱ to い え イ エ.
+func FoldArchaic() Option {
+	return func(cv *Converter) {
+		cv.archaic = true
+	}
+}
+
+func lowerG(c *character) {
+	if c.filters&FLT_LOWER_G != FLT_LOWER_G {
+		return
+	}
+	v, ok := hentaigana[string(c.val)]
+	if !ok {
+		v = string(c.val)
+	}
+	if c.cv.archaic {
+		if a, ok := archaic[v]; ok {
+			v = a
+		}
+	}
+	c.cval = []byte(v)
+	c.refeed = true
+}
+
+// isArchaic reports whether s starts with ゐ ゑ ヰ or ヱ to be converted by
+// the g mode.
+func isArchaic(c *character, s []byte) bool {
+	if c.cv == nil || !c.cv.archaic || !c.cv.has('g') {
+		return false
+	}
+	_, ok := archaic[string(s[:3])]
+	return ok
+}
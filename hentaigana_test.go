@@ -0,0 +1,28 @@
+package kanaco
+
+import "testing"
+
+func TestHentaigana(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+		opts             []Option
+	}{
+		{"𛀂𛀙𛁈", "g", "あかし", nil},
+		{"𛀂𛀙𛁈", "gC", "アカシ", nil},
+		{"𛀂𛀙𛁈", "gh", "ｱｶｼ", nil},
+		{"𛀀𛄠", "gk", "ｴｲ", nil},
+		{"𛄝", "g", "ん", nil},
+		{"𛅧", "g", "ン", nil},
+		{"𛄍ゐヱ", "g", "ゐゐヱ", nil},
+		{"𛄍ゐヱ", "g", "いいエ", []Option{FoldArchaic()}},
+		{"ゐヱ", "gC", "イエ", []Option{FoldArchaic()}},
+		{"ゐ", "C", "ヰ", []Option{FoldArchaic()}},
+		{"𛀂", "C", "𛀂", nil},
+	}
+	for _, tt := range tests {
+		result := NewConverter(tt.mode, tt.opts...).String(tt.in)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
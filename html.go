@@ -0,0 +1,317 @@
+package kanaco
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// HTMLConverter converts the text of HTML documents, leaving the markup,
+	// the character references and the content of script and style elements
+	// byte-identical.
+	HTMLConverter struct {
+		Attributes []string // names of the attributes converted too, e.g. alt and title
+		cv         *Converter
+	}
+	// htmlText is a text decoded from HTML, with the numeric character
+	// reference each rune was written in.
+	htmlText struct {
+		buf  []byte
+		refs map[int]string // formats of the references by offset in buf
+	}
+)
+
+func NewHTMLConverter(mode string, opts ...Option) *HTMLConverter {
+	return &HTMLConverter{cv: NewConverter(mode, opts...)}
+}
+
+func (h *HTMLConverter) String(s string) string {
+	return string(h.Byte([]byte(s)))
+}
+
+// Byte converts the text nodes of b and the values of Attributes. Numeric
+// character references (&#x30AB;) are converted and written as references
+// again, and named ones (&amp;) are left as they are.
+func (h *HTMLConverter) Byte(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); {
+		if b[i] != '<' {
+			n := bytes.IndexByte(b[i:], '<')
+			if n < 0 {
+				n = len(b) - i
+			}
+			out = h.text(out, b[i:i+n], 0)
+			i += n
+			continue
+		}
+		n, name := htmlMarkup(b[i:])
+		if name == "" {
+			out = append(out, b[i:i+n]...)
+			i += n
+			continue
+		}
+		out = h.tag(out, b[i:i+n])
+		i += n
+		if (name == "script" || name == "style") && !bytes.HasSuffix(b[i-n:i], []byte("/>")) {
+			// raw text up to the end tag
+			end := indexFold(b[i:], "</"+name)
+			if end < 0 {
+				end = len(b) - i
+			}
+			out = append(out, b[i:i+end]...)
+			i += end
+		}
+	}
+	return out
+}
+
+// htmlMarkup returns the length of the markup at the head of b, which starts
+// with '<', and the lower-cased name when it is a start tag. A '<' that does
+// not start markup is a single byte of text.
+func htmlMarkup(b []byte) (int, string) {
+	closeAt := func(s string) int {
+		if n := bytes.Index(b, []byte(s)); n >= 0 {
+			return n + len(s)
+		}
+		return len(b)
+	}
+	switch {
+	case bytes.HasPrefix(b, []byte("<!--")):
+		return closeAt("-->"), ""
+	case bytes.HasPrefix(b, []byte("<![CDATA[")):
+		return closeAt("]]>"), ""
+	case len(b) > 1 && (b[1] == '!' || b[1] == '?' || b[1] == '/'):
+		return closeAt(">"), ""
+	case len(b) > 1 && isASCIILetter(b[1]):
+	default:
+		return 1, ""
+	}
+	n := 1
+	for n < len(b) && !isHTMLSpace(b[n]) && b[n] != '/' && b[n] != '>' {
+		n++
+	}
+	name := strings.ToLower(string(b[1:n]))
+	// attribute values may contain '>'
+	for n < len(b) && b[n] != '>' {
+		if b[n] == '"' || b[n] == '\'' {
+			if q := bytes.IndexByte(b[n+1:], b[n]); q >= 0 {
+				n += q + 1
+			} else {
+				n = len(b) - 1
+			}
+		}
+		n++
+	}
+	if n < len(b) {
+		n++
+	}
+	return n, name
+}
+
+// tag appends the start tag b to out, converting the values of Attributes.
+func (h *HTMLConverter) tag(out, b []byte) []byte {
+	if len(h.Attributes) == 0 {
+		return append(out, b...)
+	}
+	i := 1
+	for i < len(b) && !isHTMLSpace(b[i]) && b[i] != '/' && b[i] != '>' {
+		i++
+	}
+	out = append(out, b[:i]...)
+	for i < len(b) {
+		// attribute name
+		start := i
+		for i < len(b) && (isHTMLSpace(b[i]) || b[i] == '/') {
+			i++
+		}
+		for i < len(b) && !isHTMLSpace(b[i]) && b[i] != '=' && b[i] != '>' && b[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(strings.TrimLeft(string(b[start:i]), " \t\r\n\f/"))
+		for i < len(b) && isHTMLSpace(b[i]) {
+			i++
+		}
+		if i >= len(b) || b[i] != '=' {
+			out = append(out, b[start:i]...)
+			if i < len(b) && b[i] == '>' {
+				return append(out, b[i:]...)
+			}
+			continue
+		}
+		i++
+		for i < len(b) && isHTMLSpace(b[i]) {
+			i++
+		}
+		out = append(out, b[start:i]...)
+		// attribute value
+		quote := byte(0)
+		if i < len(b) && (b[i] == '"' || b[i] == '\'') {
+			quote = b[i]
+			out = append(out, quote)
+			i++
+		}
+		vs := i
+		for i < len(b) && ((quote != 0 && b[i] != quote) || (quote == 0 && !isHTMLSpace(b[i]) && b[i] != '>')) {
+			i++
+		}
+		if h.converts(name) && quote == 0 {
+			out = h.text(out, b[vs:i], ' ')
+		} else if h.converts(name) {
+			out = h.text(out, b[vs:i], quote)
+		} else {
+			out = append(out, b[vs:i]...)
+		}
+		if quote != 0 && i < len(b) {
+			out = append(out, quote)
+			i++
+		}
+	}
+	return out
+}
+
+func (h *HTMLConverter) converts(attr string) bool {
+	for _, a := range h.Attributes {
+		if strings.EqualFold(a, attr) {
+			return true
+		}
+	}
+	return false
+}
+
+// text appends the converted text b to out. Named character references split
+// the text and are kept as they are. quote is the quote of an attribute
+// value, ' ' for an unquoted value or 0 for text nodes.
+func (h *HTMLConverter) text(out, b []byte, quote byte) []byte {
+	for len(b) > 0 {
+		n := namedRef(b)
+		if n > 0 {
+			out = append(out, b[:n]...)
+			b = b[n:]
+			continue
+		}
+		end := 0
+		for end < len(b) && (b[end] != '&' || namedRef(b[end:]) == 0) {
+			end++
+		}
+		out = h.segment(out, b[:end], quote)
+		b = b[end:]
+	}
+	return out
+}
+
+// segment converts a text without named character references.
+func (h *HTMLConverter) segment(out, b []byte, quote byte) []byte {
+	t := decodeHTML(b)
+	conv, m := h.cv.ConvertWithMap(t.buf)
+	if bytes.Equal(conv, t.buf) {
+		return append(out, b...)
+	}
+	enc := []byte{}
+	unquoted := false
+	for i := 0; i < len(conv); {
+		r, n := utf8.DecodeRune(conv[i:])
+		ref := t.refs[m.ToOriginal(i)]
+		switch {
+		case ref != "":
+			enc = append(enc, fmt.Sprintf(ref, r)...)
+		case r == '&':
+			enc = append(enc, "&amp;"...)
+		case r == '<':
+			enc = append(enc, "&lt;"...)
+		case r == '>':
+			enc = append(enc, "&gt;"...)
+		case r == '"' && quote == '"':
+			enc = append(enc, "&quot;"...)
+		case r == '\'' && quote == '\'':
+			enc = append(enc, "&#39;"...)
+		default:
+			unquoted = unquoted || isHTMLSpace(byte(r)) || r == '"' || r == '\'' || r == '=' || r == '`'
+			enc = append(enc, conv[i:i+n]...)
+		}
+		i += n
+	}
+	if quote == ' ' && unquoted {
+		// an unquoted attribute value that needs quotes now
+		return append(append(append(out, '"'), bytes.ReplaceAll(enc, []byte(`"`), []byte("&quot;"))...), '"')
+	}
+	return append(out, enc...)
+}
+
+// decodeHTML decodes the numeric character references of b.
+func decodeHTML(b []byte) *htmlText {
+	t := &htmlText{buf: make([]byte, 0, len(b)), refs: map[int]string{}}
+	for i := 0; i < len(b); {
+		if r, n, ref := numericRef(b[i:]); n > 0 {
+			t.refs[len(t.buf)] = ref
+			t.buf = utf8.AppendRune(t.buf, r)
+			i += n
+			continue
+		}
+		t.buf = append(t.buf, b[i])
+		i++
+	}
+	return t
+}
+
+// numericRef parses the numeric character reference at the head of b and
+// returns the rune, its length and the format to write it again.
+func numericRef(b []byte) (rune, int, string) {
+	if len(b) < 4 || b[0] != '&' || b[1] != '#' {
+		return 0, 0, ""
+	}
+	i, base, ref := 2, 10, "&#%d;"
+	if b[2] == 'x' || b[2] == 'X' {
+		i, base, ref = 3, 16, "&#"+string(b[2])+"%x;"
+	}
+	end := bytes.IndexByte(b[i:], ';')
+	if end <= 0 || end > 8 {
+		return 0, 0, ""
+	}
+	digits := string(b[i : i+end])
+	n, err := strconv.ParseUint(digits, base, 32)
+	if err != nil || n > utf8.MaxRune || !utf8.ValidRune(rune(n)) {
+		return 0, 0, ""
+	}
+	if base == 16 && strings.ContainsAny(digits, "ABCDEF") {
+		ref = strings.Replace(ref, "%x", "%X", 1)
+	}
+	return rune(n), i + end + 1, ref
+}
+
+// namedRef returns the length of the named character reference at the head
+// of b, or 0.
+func namedRef(b []byte) int {
+	if len(b) < 2 || b[0] != '&' || !isASCIILetter(b[1]) {
+		return 0
+	}
+	n := 2
+	for n < len(b) && (isASCIILetter(b[n]) || (b[n] >= '0' && b[n] <= '9')) {
+		n++
+	}
+	if n < len(b) && b[n] == ';' {
+		n++
+	}
+	return n
+}
+
+// indexFold returns the index of the first instance of the ASCII string s in
+// b ignoring case, or -1.
+func indexFold(b []byte, s string) int {
+	for i := 0; i+len(s) <= len(b); i++ {
+		if strings.EqualFold(string(b[i:i+len(s)]), s) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
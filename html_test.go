@@ -0,0 +1,46 @@
+package kanaco
+
+import (
+	"testing"
+)
+
+func TestHTMLConverter(t *testing.T) {
+	data := []struct {
+		mode   string
+		in     string
+		expect string
+	}{
+		{"KV", `<p class="ｶﾅ">ｶﾅ</p>`, `<p class="ｶﾅ">カナ</p>`},
+		{"KV", `<!DOCTYPE html><!-- ｶﾅ --><p>ｱ<br/>ｲ</p>`, `<!DOCTYPE html><!-- ｶﾅ --><p>ア<br/>イ</p>`},
+		{"KV", `<script>var s = "ｶﾅ";</script><STYLE>p:after{content:"ｱ"}</style>ｱ`, `<script>var s = "ｶﾅ";</script><STYLE>p:after{content:"ｱ"}</style>ア`},
+		{"KV", `<a title="x>y">ｱ</a>`, `<a title="x>y">ア</a>`},
+		{"KV", `&#xFF76;&#xff9e; &#65393; ｶ&#65438;`, `&#x30AC; &#12450; ガ`},
+		{"KV", `ｱ&amp;ｲ&nbsp;ｳ &copy ｴ`, `ア&amp;イ&nbsp;ウ &copy エ`},
+		{"KV", `&#0; &#xzz; &#;`, `&#0; &#xzz; &#;`},
+		{"a", `＜b＞＆&#xFF1C;`, `&lt;b&gt;&amp;&#x3C;`},
+		{"KV", `1 < 2 ｱ`, `1 < 2 ア`},
+		{"KV", `<p>ｱ`, `<p>ア`},
+		{"j", "\xe3\x81が千", "\xe3\x81が1000"},
+		{"j", "\xe3\x81&#x5343; &#x5343;", "\xe3\x81&#x5343; &#x31;&#x30;&#x30;&#x30;"},
+		{"KV", "\xffｱ&#xff72;\xe3", "\xffｱ&#x30a4;\xe3"},
+	}
+	for _, v := range data {
+		if result := NewHTMLConverter(v.mode).String(v.in); result != v.expect {
+			t.Errorf("[%s] expect %s, result %s", v.in, v.expect, result)
+		}
+	}
+
+	h := NewHTMLConverter("KVS")
+	h.Attributes = []string{"alt", "TITLE"}
+	in := `<img src="ｱ.png" alt="ｱ ｲ" Title='ｳ"&#xff74;' data-x=ｵ><input value=ｶ alt=ｱ　ｲ disabled>`
+	expect := `<img src="ｱ.png" alt="ア　イ" Title='ウ"&#x30a8;' data-x=ｵ><input value=ｶ alt=ア　イ disabled>`
+	if result := h.String(in); result != expect {
+		t.Errorf("expect %s, result %s", expect, result)
+	}
+	h = NewHTMLConverter("s")
+	h.Attributes = []string{"alt"}
+	in, expect = `<img alt=ｱ　ｲ>`, `<img alt="ｱ ｲ">`
+	if result := h.String(in); result != expect {
+		t.Errorf("expect %s, result %s", expect, result)
+	}
+}
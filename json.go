@@ -0,0 +1,322 @@
+package kanaco
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type (
+	// JSONTransformer converts the string values of JSON texts, and
+	// optionally the keys, leaving the rest byte-identical. Strings are
+	// selected by JSON Pointer patterns, in which "*" matches a member or an
+	// element and "**" matches any number of them.
+	JSONTransformer struct {
+		Keys  bool // convert the keys of objects too
+		cv    *Converter
+		paths []jsonPath
+	}
+	jsonPath struct {
+		pattern []string
+		cv      *Converter
+	}
+	// jsonFrame is an object or an array being read.
+	jsonFrame struct {
+		object bool
+		key    string
+		index  int
+		colon  bool // the key of the current member has been read
+	}
+)
+
+var ErrJSONSyntax = errors.New("kanaco: invalid JSON")
+
+// NewJSONTransformer returns a JSONTransformer that converts the strings
+// matched by no path with mode, or leaves them as they are when mode is
+// empty.
+func NewJSONTransformer(mode string, opts ...Option) *JSONTransformer {
+	t := &JSONTransformer{}
+	if mode != "" {
+		t.cv = NewConverter(mode, opts...)
+	}
+	return t
+}
+
+// SetPath converts the strings matched by pattern, e.g. "/users/*/kana" or
+// "/**/name", with mode. The first pattern set that matches is used.
+func (t *JSONTransformer) SetPath(pattern, mode string, opts ...Option) error {
+	if pattern != "" && pattern[0] != '/' {
+		return fmt.Errorf("kanaco: invalid JSON pointer %q", pattern)
+	}
+	t.paths = append(t.paths, jsonPath{pattern: splitPointer(pattern), cv: NewConverter(mode, opts...)})
+	return nil
+}
+
+// Transform reads JSON texts from r and writes them to w with the selected
+// strings converted. Escaped characters (\u30ab) are converted too, and a
+// converted string is escaped again when it had non-ASCII escapes.
+func (t *JSONTransformer) Transform(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	stack := []*jsonFrame{}
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		switch b {
+		case '{', '[':
+			stack = append(stack, &jsonFrame{object: b == '{'})
+		case '}', ']':
+			if top == nil || top.object != (b == '}') {
+				return ErrJSONSyntax
+			}
+			stack = stack[:len(stack)-1]
+		case ':':
+			if top == nil || !top.object {
+				return ErrJSONSyntax
+			}
+			top.colon = true
+		case ',':
+			if top != nil && top.object {
+				top.colon = false
+			} else if top != nil {
+				top.index++
+			}
+		case '"':
+			raw, s, ascii, err := readJSONString(br)
+			if err != nil {
+				return err
+			}
+			key := top != nil && top.object && !top.colon
+			if key {
+				top.key = s
+			}
+			cv := t.converter(stack)
+			if cv == nil || (key && !t.Keys) {
+				bw.WriteByte('"')
+				bw.Write(raw)
+				continue
+			}
+			if cs := cv.String(s); cs != s {
+				writeJSONString(bw, cs, ascii)
+			} else {
+				bw.WriteByte('"')
+				bw.Write(raw)
+			}
+			continue
+		}
+		bw.WriteByte(b)
+	}
+	if len(stack) > 0 {
+		return ErrJSONSyntax
+	}
+	return bw.Flush()
+}
+
+// converter returns the Converter of the string at the current position.
+func (t *JSONTransformer) converter(stack []*jsonFrame) *Converter {
+	if len(t.paths) == 0 {
+		return t.cv
+	}
+	path := make([]string, 0, len(stack))
+	for _, f := range stack {
+		if f.object {
+			path = append(path, f.key)
+		} else {
+			path = append(path, strconv.Itoa(f.index))
+		}
+	}
+	for _, p := range t.paths {
+		if matchPointer(p.pattern, path) {
+			return p.cv
+		}
+	}
+	return t.cv
+}
+
+// readJSONString reads a string after its opening quote. It returns the raw
+// bytes including the closing quote, the decoded string and whether it had
+// escaped non-ASCII characters. A lone surrogate (\ud800) is decoded to its
+// three byte encoding, which is not valid UTF-8, so that it is written back
+// as an escape.
+func readJSONString(br *bufio.Reader) ([]byte, string, bool, error) {
+	raw := []byte{}
+	s := strings.Builder{}
+	ascii := false
+	pending := rune(-1) // a high surrogate
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, "", false, ErrJSONSyntax
+		}
+		raw = append(raw, b)
+		if b != '\\' && pending >= 0 {
+			writeSurrogate(&s, pending)
+			pending = -1
+		}
+		if b == '"' {
+			return raw, s.String(), ascii, nil
+		}
+		if b != '\\' {
+			s.WriteByte(b)
+			continue
+		}
+		e, err := br.ReadByte()
+		if err != nil {
+			return nil, "", false, ErrJSONSyntax
+		}
+		raw = append(raw, e)
+		if e != 'u' && pending >= 0 {
+			writeSurrogate(&s, pending)
+			pending = -1
+		}
+		switch e {
+		case '"', '\\', '/':
+			s.WriteByte(e)
+		case 'b':
+			s.WriteByte('\b')
+		case 'f':
+			s.WriteByte('\f')
+		case 'n':
+			s.WriteByte('\n')
+		case 'r':
+			s.WriteByte('\r')
+		case 't':
+			s.WriteByte('\t')
+		case 'u':
+			hex := make([]byte, 4)
+			if _, err := io.ReadFull(br, hex); err != nil {
+				return nil, "", false, ErrJSONSyntax
+			}
+			raw = append(raw, hex...)
+			n, err := strconv.ParseUint(string(hex), 16, 16)
+			if err != nil {
+				return nil, "", false, ErrJSONSyntax
+			}
+			r := rune(n)
+			if r >= 0x80 {
+				ascii = true
+			}
+			switch {
+			case pending >= 0 && utf16.IsSurrogate(r) && r >= 0xdc00:
+				s.WriteRune(utf16.DecodeRune(pending, r))
+				pending = -1
+			case utf16.IsSurrogate(r) && r < 0xdc00:
+				if pending >= 0 {
+					writeSurrogate(&s, pending)
+				}
+				pending = r
+			default:
+				if pending >= 0 {
+					writeSurrogate(&s, pending)
+					pending = -1
+				}
+				if utf16.IsSurrogate(r) {
+					writeSurrogate(&s, r)
+				} else {
+					s.WriteRune(r)
+				}
+			}
+		default:
+			return nil, "", false, ErrJSONSyntax
+		}
+	}
+}
+
+// writeSurrogate writes the lone surrogate r in the way UTF-8 would encode
+// it if it were a character.
+func writeSurrogate(s *strings.Builder, r rune) {
+	s.WriteByte(byte(0xe0 | r>>12))
+	s.WriteByte(byte(0x80 | r>>6&0x3f))
+	s.WriteByte(byte(0x80 | r&0x3f))
+}
+
+// surrogateAt returns the lone surrogate written by writeSurrogate at the
+// head of s, or -1.
+func surrogateAt(s string) rune {
+	if len(s) < 3 || s[0] != 0xed || s[1] < 0xa0 || s[1] > 0xbf || s[2] < 0x80 || s[2] > 0xbf {
+		return -1
+	}
+	return rune(s[0]&0x0f)<<12 | rune(s[1]&0x3f)<<6 | rune(s[2]&0x3f)
+}
+
+// writeJSONString writes s as a JSON string, escaping the non-ASCII
+// characters when ascii is set and the lone surrogates.
+func writeJSONString(w *bufio.Writer, s string, ascii bool) {
+	w.WriteByte('"')
+	for i := 0; i < len(s); {
+		if r := surrogateAt(s[i:]); r >= 0 {
+			fmt.Fprintf(w, `\u%04x`, r)
+			i += 3
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		i += n
+		switch {
+		case r == '"' || r == '\\':
+			w.WriteByte('\\')
+			w.WriteRune(r)
+		case r == '\n':
+			w.WriteString(`\n`)
+		case r == '\r':
+			w.WriteString(`\r`)
+		case r == '\t':
+			w.WriteString(`\t`)
+		case r < 0x20:
+			fmt.Fprintf(w, `\u%04x`, r)
+		case r >= 0x80 && ascii:
+			if r > 0xffff {
+				r1, r2 := utf16.EncodeRune(r)
+				fmt.Fprintf(w, `\u%04x\u%04x`, r1, r2)
+			} else {
+				fmt.Fprintf(w, `\u%04x`, r)
+			}
+		default:
+			w.WriteRune(r)
+		}
+	}
+	w.WriteByte('"')
+}
+
+// splitPointer splits a JSON Pointer into its unescaped reference tokens.
+func splitPointer(p string) []string {
+	if p == "" {
+		return []string{}
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, tk := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tk, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func matchPointer(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchPointer(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 || (pattern[0] != "*" && pattern[0] != path[0]) {
+		return false
+	}
+	return matchPointer(pattern[1:], path[1:])
+}
@@ -0,0 +1,50 @@
+package kanaco
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJSONTransformer(t *testing.T) {
+	data := []struct {
+		in     string
+		expect string
+	}{
+		{`{"ｶﾅ": "ｶﾅ", "n": [1, "ｱ", {"x":"ｲ"}], "t": true}`, `{"ｶﾅ": "カナ", "n": [1, "ア", {"x":"イ"}], "t": true}`},
+		{`["\uff76\uff9e", "\u30ab", "a\"\\ｱ\n", "\ud83d\ude00ｱ"]`, `["\u30ac", "\u30ab", "a\"\\ア\n", "\ud83d\ude00\u30a2"]`},
+		{`"ｱ" "ｲ"` + "\n", `"ア" "イ"` + "\n"},
+		{`{"a" : "\/"}`, `{"a" : "\/"}`},
+		{`["\ud800ｱ", "ｱ\udc00\ud83d", "\ud83d\ud83d\ude00ｱ"]`, `["\ud800\u30a2", "\u30a2\udc00\ud83d", "\ud83d\ud83d\ude00\u30a2"]`},
+		{`"\ud800" "\udfff"`, `"\ud800" "\udfff"`},
+	}
+	tr := NewJSONTransformer("KV")
+	for _, v := range data {
+		buf := &bytes.Buffer{}
+		if err := tr.Transform(buf, strings.NewReader(v.in)); err != nil || buf.String() != v.expect {
+			t.Errorf("[%s] expect %s, result %s %v", v.in, v.expect, buf.String(), err)
+		}
+	}
+
+	tr = NewJSONTransformer("")
+	tr.Keys = true
+	tr.SetPath("/users/*/kana", "KV")
+	tr.SetPath("/users/*/tel", "a")
+	tr.SetPath("/**/ﾒﾓ", "H")
+	tr.SetPath("/a~1b", "KV")
+	in := `{"users": [{"kana": "ﾔﾏﾀﾞ", "tel": "０３", "name": "ｶﾅ"}], "x": {"y": {"ﾒﾓ": "ﾒﾓ"}}, "a/b": "ｱ", "kana": "ｱ"}`
+	expect := `{"users": [{"kana": "ヤマダ", "tel": "03", "name": "ｶﾅ"}], "x": {"y": {"めも": "めも"}}, "a/b": "ア", "kana": "ｱ"}`
+	buf := &bytes.Buffer{}
+	if err := tr.Transform(buf, strings.NewReader(in)); err != nil || buf.String() != expect {
+		t.Errorf("expect %s, result %s %v", expect, buf.String(), err)
+	}
+
+	if err := tr.SetPath("users", "KV"); err == nil {
+		t.Errorf("expect an error")
+	}
+	for _, in := range []string{`{"a": 1`, `[1}`, `"ｱ`, `"\x"`, `"\u30zz"`, `]`} {
+		if err := NewJSONTransformer("KV").Transform(&bytes.Buffer{}, strings.NewReader(in)); err != ErrJSONSyntax {
+			t.Errorf("[%s] expect %v, result %v", in, ErrJSONSyntax, err)
+		}
+	}
+}
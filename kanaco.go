@@ -2,8 +2,11 @@ package kanaco
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -23,17 +26,56 @@ const (
 	FLT_UPPER_H int = 1 << 11
 	FLT_LOWER_C int = 1 << 12
 	FLT_UPPER_C int = 1 << 13
+	FLT_LOWER_E int = 1 << 14
+	FLT_LOWER_Q int = 1 << 15
+	FLT_LOWER_M int = 1 << 16
+	FLT_UPPER_M int = 1 << 17
+	FLT_LOWER_J int = 1 << 18
+	FLT_UPPER_J int = 1 << 19
+	FLT_LOWER_G int = 1 << 20
+	FLT_LOWER_Y int = 1 << 21
+	FLT_LOWER_L int = 1 << 22
+	FLT_UPPER_L int = 1 << 23
 )
 
+// modes are the valid mode letters. V joins hankaku kana and their voiced
+// sound marks as in mb_convert_kana, which K and H always do.
+const modes = "rRnNaAsSkKhHcCeqmMjJgylLV"
+
+var ErrMode = errors.New("kanaco: invalid mode")
+
+// foldModes are the modes whose output is converted again by the rest of
+// the modes, e.g. "eN" turns ⑫ into "12" and then into "１２".
+const foldModes = "eqmjgyL"
+
 type (
+	Converter struct {
+		mode    string
+		letters [128]bool // the letters of mode, looked up for every character
+		filters []filter
+		base    []filter // filters applied to the output of fold modes
+		paren   bool
+		kanji   KanjiStyle
+		archaic bool
+		reading ReadingRules
+		protect []Protector
+		except  map[string]bool
+		only    map[string]bool // nil when every character is converted
+	}
+	Option func(*Converter)
 	Reader struct {
-		r    *bufio.Reader
-		mode string
+		r  *bufio.Reader
+		cv *Converter
 	}
 	character struct {
 		val     []byte
 		cval    []byte // converted value
 		filters int    // FLT_LOWER_* or FLT_UPPER_*
+		refeed  bool   // cval is converted again by the base filters
+		drop    bool   // the character is removed
+		prev    []byte // val of the preceding character
+		before  []byte // the text before the character
+		cv      *Converter
 	}
 	filter func(*character)
 )
@@ -42,16 +84,133 @@ func Byte(b []byte, mode string) []byte {
 	if len(mode) == 0 {
 		return []byte{}
 	}
-	filters := createFilters(mode)
+	return NewConverter(mode).Byte(b)
+}
+
+func String(str, mode string) string {
+	return string(Byte([]byte(str), mode))
+}
+
+func NewReader(r io.Reader, mode string) *Reader {
+	return NewConverter(mode).NewReader(r)
+}
+
+// NewConverter returns a Converter for mode. A Converter can be reused and
+// is safe for concurrent use.
+func NewConverter(mode string, opts ...Option) *Converter {
+	cv := new(Converter)
+	cv.setMode(mode)
+	cv.reading = ReadingDefault
+	for _, opt := range opts {
+		opt(cv)
+	}
+	return cv
+}
+
+// setMode sets the mode and the filters for it.
+func (cv *Converter) setMode(mode string) {
+	cv.mode = mode
+	cv.letters = [128]bool{}
+	for _, m := range []byte(mode) {
+		if m < 128 {
+			cv.letters[m] = true
+		}
+	}
+	cv.filters = createFilters(mode)
+	cv.base = createFilters(baseMode(mode))
+}
+
+// baseMode returns the letters of mode that are not fold modes.
+func baseMode(mode string) string {
+	base := strings.Builder{}
+	for _, m := range []byte(mode) {
+		if strings.IndexByte(foldModes, m) < 0 {
+			base.WriteByte(m)
+		}
+	}
+	return base.String()
+}
+
+// CheckMode returns an error wrapping ErrMode when mode contains a letter
+// that is not a mode.
+func CheckMode(mode string) error {
+	for _, m := range []byte(mode) {
+		if strings.IndexByte(modes, m) < 0 {
+			return fmt.Errorf("%w: %q", ErrMode, m)
+		}
+	}
+	return nil
+}
+
+// KeepParentheses makes the e mode keep the brackets of parenthesized
+// characters (⑴ -> (1), ㈱ -> (株)) instead of dropping them (⑴ -> 1).
+func KeepParentheses() Option {
+	return func(cv *Converter) {
+		cv.paren = true
+	}
+}
+
+func (cv *Converter) Byte(b []byte) []byte {
+	return cv.run(b, cv.filters, nil)
+}
+
+func (cv *Converter) String(str string) string {
+	return string(cv.Byte([]byte(str)))
+}
+
+func (cv *Converter) NewReader(r io.Reader) *Reader {
+	reader := new(Reader)
+	reader.r = bufio.NewReader(r)
+	reader.cv = cv
+	return reader
+}
+
+func (cv *Converter) has(m byte) bool {
+	return m < 128 && cv.letters[m]
+}
+
+func (cv *Converter) run(b []byte, filters []filter, m *OffsetMap) []byte {
+	return cv.scan(b, filters, m, cv.protected(b))
+}
 
+// scan converts b with filters, copying the protected spans as they are. A
+// character never extends into a protected span.
+func (cv *Converter) scan(b []byte, filters []filter, m *OffsetMap, spans [][]int) []byte {
 	buf := make([]byte, 0, 512)
 	c := new(character)
+	prev := []byte{}
 	length := len(b)
 	for i := 0; i < length; i++ {
+		limit := length
+		if len(spans) > 0 {
+			if i >= spans[0][0] {
+				span := b[spans[0][0]:spans[0][1]]
+				if m != nil {
+					m.add(i, len(buf), span, span)
+				}
+				buf = append(buf, span...)
+				_, n := utf8.DecodeLastRune(span)
+				prev = span[len(span)-n:]
+				i = spans[0][1] - 1
+				spans = spans[1:]
+				continue
+			}
+			limit = spans[0][0]
+		}
 		c.init()
-		extract(c, b[i:])
-		conv(c, filters)
+		c.cv = cv
+		c.prev = prev
+		c.before = b[:i]
+		extract(c, b[i:limit])
+		if cv.excluded(c.val) {
+			c.filters = FLT_ASIS
+		}
+		cv.conv(c, filters)
+		if m != nil {
+			m.add(i, len(buf), c.val, c.cval)
+		}
 		buf = append(buf, c.cval...)
+		prev = c.val
 		proseed := len(c.val) - 1
 		if proseed < 0 {
 			proseed = 0
@@ -61,15 +220,17 @@ func Byte(b []byte, mode string) []byte {
 	return buf
 }
 
-func String(str, mode string) string {
-	return string(Byte([]byte(str), mode))
-}
-
-func NewReader(r io.Reader, mode string) *Reader {
-	reader := new(Reader)
-	reader.r = bufio.NewReader(r)
-	reader.mode = mode
-	return reader
+func (cv *Converter) conv(c *character, filters []filter) []byte {
+	for _, f := range filters {
+		f(c)
+	}
+	if c.refeed {
+		c.cval = cv.scan(c.cval, cv.base, nil, nil)
+	}
+	if len(c.cval) == 0 && !c.drop {
+		asis(c)
+	}
+	return c.cval
 }
 
 func (r *Reader) Read(p []byte) (int, error) {
@@ -80,7 +241,7 @@ func (r *Reader) Read(p []byte) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	line = Byte(line, r.mode)
+	line = r.cv.Byte(line)
 	if len(p) < len(line) {
 		return 0, fmt.Errorf("buffer size is not enough")
 	}
@@ -94,6 +255,11 @@ func (c *character) init() {
 	c.val = []byte{}
 	c.cval = []byte{}
 	c.filters = FLT_ASIS
+	c.refeed = false
+	c.drop = false
+	c.prev = nil
+	c.before = nil
+	c.cv = nil
 }
 
 func is1Byte(b []byte) bool {
@@ -826,7 +992,13 @@ func extract(c *character, s []byte) {
 	length := 1
 	if is1Byte(s) {
 		c0 := s[0]
-		if c0 == 0x20 { // Space
+		if n := romanLen(c, s); n > 0 { // I - XII
+			length = n
+			c.filters = FLT_UPPER_M
+		} else if n := digitLen(c, s); n > 0 { // 0 - 9
+			length = n
+			c.filters = FLT_UPPER_J
+		} else if c0 == 0x20 { // Space
 			c.filters = FLT_UPPER_S
 		} else if c0 >= 0x30 && c0 <= 0x39 { // 0 - 9
 			c.filters = FLT_UPPER_A | FLT_UPPER_N
@@ -837,10 +1009,32 @@ func extract(c *character, s []byte) {
 		} else if c0 >= 0x21 && c0 <= 0x7d && c0 != 0x22 && c0 != 0x27 && c0 != 0x5c {
 			c.filters = FLT_UPPER_A
 		}
+	} else if is4Bytes(s) {
+		length = 4
+		if _, ok := enclosed[string(s[:4])]; ok { // 🄀 - 🉑
+			c.filters = FLT_LOWER_E
+		} else if _, ok := squared[string(s[:4])]; ok { // 🈀 - 🈂
+			c.filters = FLT_LOWER_Q
+		} else if _, ok := hentaigana[string(s[:4])]; ok { // 𛀀 - 𛅧
+			c.filters = FLT_LOWER_G
+		}
 	} else if is3Bytes(s) {
 		length = 3
 		c0, c1, c2 := s[0], s[1], s[2]
-		if c0 == 0xef {
+		if n := kanjiNumberLen(c, s); n > 0 { // 〇 一 - 九 十 百 千 万 億 兆 京
+			length = n
+			c.filters = FLT_LOWER_J
+		} else if n := digitLen(c, s); n > 0 { // ０ - ９
+			length = n
+			c.filters = FLT_UPPER_J
+		} else if isArchaic(c, s) { // ゐ ゑ ヰ ヱ
+			c.filters = FLT_LOWER_G
+		} else if n := readingLen(c, s); n > 0 { // ヂ ヅ ヴ ヰ ヱ ヲ ウ オ
+			length = n
+			c.filters = FLT_LOWER_Y
+		} else if f := longVowel(c, s); f != FLT_ASIS { // ー ｰ
+			c.filters = f
+		} else if c0 == 0xef {
 			if c1 == 0xbc {
 				if c2 >= 0x90 && c2 <= 0x99 { // ０ - ９
 					c.filters = FLT_LOWER_A | FLT_LOWER_N
@@ -912,28 +1106,30 @@ func extract(c *character, s []byte) {
 				} else if c2 >= 0xbd && c2 <= 0xbe { // ヽ ヾ
 					c.filters = FLT_LOWER_C
 				}
+			} else if c1 >= 0x88 && c1 <= 0x8f { // ㈠ - ㏿
+				if _, ok := enclosed[string(s[:3])]; ok {
+					c.filters = FLT_LOWER_E
+				} else if _, ok := squared[string(s[:3])]; ok {
+					c.filters = FLT_LOWER_Q
+				}
+			}
+		} else if c0 == 0xe2 {
+			if c1 == 0x85 && c2 >= 0xa0 { // Ⅰ - ⅿ
+				c.filters = FLT_LOWER_M
+			} else if (c1 >= 0x91 && c1 <= 0x93) || (c1 >= 0x9d && c1 <= 0x9e) { // ① - ⓿, ❶ - ➓
+				if _, ok := enclosed[string(s[:3])]; ok {
+					c.filters = FLT_LOWER_E
+				}
 			}
 		}
 	} else if is2Bytes(s) {
 		length = 2
-	} else if is4Bytes(s) {
-		length = 4
 	} else {
 		length = 1
 	}
 	c.val = s[:length]
 }
 
-func conv(c *character, filters []filter) []byte {
-	for _, f := range filters {
-		f(c)
-	}
-	if len(c.cval) == 0 {
-		asis(c)
-	}
-	return c.cval
-}
-
 func createFilters(mode string) []filter {
 	filters := []filter{}
 	exists := map[byte]bool{}
@@ -984,6 +1180,36 @@ func createFilters(mode string) []filter {
 		case 'C':
 			filters = append(filters, upperC)
 			exists[m] = true
+		case 'e':
+			filters = append(filters, lowerE)
+			exists[m] = true
+		case 'q':
+			filters = append(filters, lowerQ)
+			exists[m] = true
+		case 'm':
+			filters = append(filters, lowerM)
+			exists[m] = true
+		case 'M':
+			filters = append(filters, upperM)
+			exists[m] = true
+		case 'j':
+			filters = append(filters, lowerJ)
+			exists[m] = true
+		case 'J':
+			filters = append(filters, upperJ)
+			exists[m] = true
+		case 'g':
+			filters = append(filters, lowerG)
+			exists[m] = true
+		case 'y':
+			filters = append(filters, lowerY)
+			exists[m] = true
+		case 'l':
+			filters = append(filters, lowerL)
+			exists[m] = true
+		case 'L':
+			filters = append(filters, upperL)
+			exists[m] = true
 		}
 	}
 	return filters
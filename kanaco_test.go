@@ -2,6 +2,7 @@ package kanaco
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -128,6 +129,19 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestCheckMode(t *testing.T) {
+	for _, mode := range []string{"", "KV", "rRnNaAsSkKhHcCeqmMjJgylL"} {
+		if err := CheckMode(mode); err != nil {
+			t.Errorf("[%s] expect nil, result %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"x", "Ka ", "kZ"} {
+		if err := CheckMode(mode); !errors.Is(err, ErrMode) {
+			t.Errorf("[%s] expect %v, result %v", mode, ErrMode, err)
+		}
+	}
+}
+
 func TestNewReader(t *testing.T) {
 	f, _ := os.Open("./data/input.txt")
 	r := NewReader(f, "a")
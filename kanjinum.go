@@ -0,0 +1,414 @@
+package kanaco
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type KanjiStyle int
+
+const (
+	KanjiMultiplicative KanjiStyle = iota // 一万二千三百四十五
+	KanjiPositional                       // 一二三四五
+	KanjiDaiji                            // 壱万弐千参百四拾五
+)
+
+var (
+	ErrKanjiNumber   = errors.New("kanaco: invalid kanji number")
+	ErrKanjiOverflow = errors.New("kanaco: kanji number out of range")
+)
+
+const kanjiMinus = "マイナス"
+
+var (
+	kanjiDigits = map[rune]int64{
+		'〇': 0, '零': 0,
+		'一': 1, '壱': 1, '壹': 1, '弌': 1,
+		'二': 2, '弐': 2, '貳': 2, '弍': 2,
+		'三': 3, '参': 3, '參': 3, '弎': 3,
+		'四': 4, '肆': 4,
+		'五': 5, '伍': 5,
+		'六': 6, '陸': 6,
+		'七': 7, '漆': 7, '柒': 7,
+		'八': 8, '捌': 8,
+		'九': 9, '玖': 9,
+	}
+	kanjiSmallUnits = map[rune]int64{
+		'十': 10, '拾': 10, '什': 10,
+		'百': 100, '佰': 100, '陌': 100,
+		'千': 1000, '阡': 1000, '仟': 1000,
+	}
+	kanjiLargeUnits = map[rune]int64{
+		'万': 1e4, '萬': 1e4,
+		'億': 1e8,
+		'兆': 1e12,
+		'京': 1e16,
+	}
+	kanjiFormats = map[KanjiStyle]struct {
+		digits []string
+		units  []string // 十 百 千
+		one    bool     // write 一 before 十 百 千
+	}{
+		KanjiMultiplicative: {[]string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"}, []string{"十", "百", "千"}, false},
+		KanjiPositional:     {[]string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"}, nil, false},
+		KanjiDaiji:          {[]string{"零", "壱", "弐", "参", "四", "五", "六", "七", "八", "九"}, []string{"拾", "百", "千"}, true},
+	}
+	kanjiLargeNames = []string{"", "万", "億", "兆", "京"}
+	// kanjiCounters are the counters and units that make a run of kanji
+	// numerals before them a number.
+	kanjiCounters = []string{
+		"丁目", "丁", "番地", "番", "号", "円", "年", "月", "日", "時", "分", "秒",
+		"人", "個", "回", "階", "歳", "才", "件", "枚", "冊", "台", "匹", "点",
+		"名", "度", "倍", "割", "章", "巻", "条", "項", "位", "週", "本",
+	}
+)
+
+// ParseKanjiNumber parses a number written in kanji numerals. Both the
+// positional form (二〇二四) and the multiplicative form (二千二十四,
+// 一億二千万) are accepted, as are the daiji used in legal documents
+// (壱万弐千参百) and ASCII digits mixed with units (3万5千).
+func ParseKanjiNumber(s string) (int64, error) {
+	neg := strings.HasPrefix(s, kanjiMinus)
+	if neg {
+		s = s[len(kanjiMinus):]
+	}
+	if len(s) == 0 {
+		return 0, ErrKanjiNumber
+	}
+	var total, section, lastSmall, lastLarge int64
+	cur := int64(-1)
+	for _, r := range s {
+		if d, ok := kanjiDigit(r); ok {
+			if cur < 0 {
+				cur = 0
+			}
+			if cur > (math.MaxInt64-d)/10 {
+				return 0, ErrKanjiOverflow
+			}
+			cur = cur*10 + d
+			if lastSmall != 0 && cur > 9 {
+				return 0, ErrKanjiNumber // 十一二
+			}
+		} else if u, ok := kanjiSmallUnits[r]; ok {
+			if lastSmall != 0 && lastSmall <= u {
+				return 0, ErrKanjiNumber
+			}
+			if cur < 0 {
+				cur = 1
+			}
+			if cur > 9 {
+				return 0, ErrKanjiNumber
+			}
+			section += cur * u
+			cur = -1
+			lastSmall = u
+		} else if u, ok := kanjiLargeUnits[r]; ok {
+			if lastLarge != 0 && lastLarge <= u {
+				return 0, ErrKanjiNumber
+			}
+			if cur > 0 {
+				if cur > math.MaxInt64-section {
+					return 0, ErrKanjiOverflow
+				}
+				section += cur
+			}
+			if section == 0 {
+				return 0, ErrKanjiNumber
+			}
+			if section > (math.MaxInt64-total)/u {
+				return 0, ErrKanjiOverflow
+			}
+			total += section * u
+			section, cur, lastSmall = 0, -1, 0
+			lastLarge = u
+		} else {
+			return 0, ErrKanjiNumber
+		}
+	}
+	if cur > 0 {
+		if cur > math.MaxInt64-section {
+			return 0, ErrKanjiOverflow
+		}
+		section += cur
+	}
+	if section > math.MaxInt64-total {
+		return 0, ErrKanjiOverflow
+	}
+	total += section
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// FormatKanjiNumber formats n in kanji numerals of the given style.
+// Negative numbers are prefixed with マイナス.
+func FormatKanjiNumber(n int64, style KanjiStyle) string {
+	f, ok := kanjiFormats[style]
+	if !ok {
+		f = kanjiFormats[KanjiMultiplicative]
+	}
+	buf := strings.Builder{}
+	u := uint64(n)
+	if n < 0 {
+		buf.WriteString(kanjiMinus)
+		u = uint64(-(n + 1)) + 1
+	}
+	if u == 0 {
+		buf.WriteString(f.digits[0])
+		return buf.String()
+	}
+	if f.units == nil {
+		digits := []byte{}
+		for ; u > 0; u /= 10 {
+			digits = append(digits, byte(u%10))
+		}
+		for i := len(digits) - 1; i >= 0; i-- {
+			buf.WriteString(f.digits[digits[i]])
+		}
+		return buf.String()
+	}
+	sections := []uint64{}
+	for ; u > 0; u /= 10000 {
+		sections = append(sections, u%10000)
+	}
+	for i := len(sections) - 1; i >= 0; i-- {
+		sec := sections[i]
+		if sec == 0 {
+			continue
+		}
+		for j, div := 3, uint64(1000); j >= 0; j, div = j-1, div/10 {
+			d := sec / div % 10
+			if d == 0 {
+				continue
+			}
+			if j == 0 || d != 1 || f.one {
+				buf.WriteString(f.digits[d])
+			}
+			if j > 0 {
+				buf.WriteString(f.units[j-1])
+			}
+		}
+		buf.WriteString(kanjiLargeNames[i])
+	}
+	return buf.String()
+}
+
+// KanjiNumbers sets the style the J mode writes numbers in.
+func KanjiNumbers(style KanjiStyle) Option {
+	return func(cv *Converter) {
+		cv.kanji = style
+	}
+}
+
+func lowerJ(c *character) {
+	if c.filters&FLT_LOWER_J != FLT_LOWER_J {
+		return
+	}
+	n, err := ParseKanjiNumber(string(c.val))
+	if err != nil {
+		return
+	}
+	c.cval = []byte(strconv.FormatInt(n, 10))
+	c.refeed = true
+}
+
+func upperJ(c *character) {
+	if c.filters&FLT_UPPER_J != FLT_UPPER_J {
+		return
+	}
+	digits := []byte{}
+	for _, r := range string(c.val) {
+		if isComma(r) {
+			continue
+		}
+		if r >= '０' {
+			r -= '０' - '0'
+		}
+		digits = append(digits, byte(r))
+	}
+	style := c.cv.kanji
+	n, err := parseDigits(digits)
+	if err != nil || (digits[0] == '0' && len(digits) > 1) {
+		style = KanjiPositional
+	}
+	if style == KanjiPositional {
+		buf := strings.Builder{}
+		for _, d := range digits {
+			buf.WriteString(kanjiFormats[KanjiPositional].digits[d-'0'])
+		}
+		c.cval = []byte(buf.String())
+		return
+	}
+	c.cval = []byte(FormatKanjiNumber(n, style))
+}
+
+func kanjiDigit(r rune) (int64, bool) {
+	if r >= '0' && r <= '9' {
+		return int64(r - '0'), true
+	}
+	d, ok := kanjiDigits[r]
+	return d, ok
+}
+
+func isKanjiNumeral(r rune) bool {
+	if _, ok := kanjiDigits[r]; ok {
+		return true
+	}
+	if _, ok := kanjiSmallUnits[r]; ok {
+		return true
+	}
+	_, ok := kanjiLargeUnits[r]
+	return ok
+}
+
+// kanjiNumberLen returns the length of the run of kanji numerals at the head
+// of s when the j mode is enabled. Numerals are a part of many words and
+// place names (統一, 千葉), so a run is a number only when a counter or a unit
+// follows it (一丁目, 二千円) or no other kanji is next to it.
+func kanjiNumberLen(c *character, s []byte) int {
+	if c.cv == nil || !c.cv.has('j') {
+		return 0
+	}
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRune(s[n:])
+		if !isKanjiNumeral(r) {
+			break
+		}
+		n += size
+	}
+	if n == 0 || hasCounter(s[n:]) {
+		return n
+	}
+	prev, _ := utf8.DecodeLastRune(c.before)
+	next, _ := utf8.DecodeRune(s[n:])
+	if unicode.Is(unicode.Han, prev) || unicode.Is(unicode.Han, next) {
+		return 0
+	}
+	return n
+}
+
+func hasCounter(s []byte) bool {
+	for _, w := range kanjiCounters {
+		if bytes.HasPrefix(s, []byte(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+// digitLen returns the length of the number at the head of s when the J mode
+// is enabled. A number is a run of ASCII and zenkaku digits, and may group
+// thousands with commas (1,000). Numbers next to a letter or a decimal point
+// (v2, 3.14) are not numbers of their own and are left to the other modes.
+func digitLen(c *character, s []byte) int {
+	if c.cv == nil || !c.cv.has('J') {
+		return 0
+	}
+	if r, _ := utf8.DecodeLastRune(c.before); isDigit(r) || isWordRune(r) || isPoint(r) {
+		return 0
+	}
+	n := digitsLen(s)
+	if n == 0 || (utf8.RuneCount(s[:n]) == 3 && groupsBefore(c.before)) {
+		return 0
+	}
+	if utf8.RuneCount(s[:n]) <= 3 {
+		for {
+			r, size := utf8.DecodeRune(s[n:])
+			if !isComma(r) {
+				break
+			}
+			g := digitsLen(s[n+size:])
+			if utf8.RuneCount(s[n+size:n+size+g]) != 3 {
+				break
+			}
+			n += size + g
+		}
+	}
+	if r, size := utf8.DecodeRune(s[n:]); isWordRune(r) {
+		return 0
+	} else if d, _ := utf8.DecodeRune(s[n+size:]); isPoint(r) && isDigit(d) {
+		return 0
+	}
+	return n
+}
+
+// groupsBefore reports whether before ends with a number that groups
+// thousands with commas followed by a comma, so that the digits after it are
+// a group of that number and not a number of their own.
+func groupsBefore(before []byte) bool {
+	for {
+		r, n := utf8.DecodeLastRune(before)
+		if !isComma(r) {
+			return false
+		}
+		before = before[:len(before)-n]
+		digits := 0
+		for {
+			r, n := utf8.DecodeLastRune(before)
+			if !isDigit(r) {
+				break
+			}
+			before = before[:len(before)-n]
+			digits++
+		}
+		if r, _ := utf8.DecodeLastRune(before); digits == 3 && isComma(r) {
+			continue
+		}
+		return digits >= 1 && digits <= 3
+	}
+}
+
+// digitsLen returns the length of the run of ASCII and zenkaku digits at the
+// head of s.
+func digitsLen(s []byte) int {
+	n := 0
+	for n < len(s) {
+		if s[n] >= 0x30 && s[n] <= 0x39 {
+			n++
+		} else if len(s) >= n+3 && s[n] == 0xef && s[n+1] == 0xbc && s[n+2] >= 0x90 && s[n+2] <= 0x99 {
+			n += 3
+		} else {
+			break
+		}
+	}
+	return n
+}
+
+func isDigit(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= '０' && r <= '９')
+}
+
+// isWordRune reports whether r is an ASCII or zenkaku letter or an
+// underscore, which make digits a part of a word such as v2 or 3D.
+func isWordRune(r rune) bool {
+	if r >= 'Ａ' && r <= 'ｚ' {
+		r -= 'Ａ' - 'A'
+	}
+	return (r < 0x80 && isLetter(byte(r))) || r == '_'
+}
+
+func isPoint(r rune) bool {
+	return r == '.' || r == '．'
+}
+
+func isComma(r rune) bool {
+	return r == ',' || r == '，'
+}
+
+func parseDigits(digits []byte) (int64, error) {
+	var n int64
+	for _, d := range digits {
+		if n > (math.MaxInt64-int64(d-'0'))/10 {
+			return 0, ErrKanjiOverflow
+		}
+		n = n*10 + int64(d-'0')
+	}
+	return n, nil
+}
@@ -0,0 +1,118 @@
+package kanaco
+
+import "testing"
+
+func TestParseKanjiNumber(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect int64
+		err    error
+	}{
+		{"〇", 0, nil},
+		{"十", 10, nil},
+		{"十二", 12, nil},
+		{"二十", 20, nil},
+		{"百五", 105, nil},
+		{"千二百三十四", 1234, nil},
+		{"二〇二四", 2024, nil},
+		{"一億二千万", 120000000, nil},
+		{"二十万三千", 203000, nil},
+		{"壱万弐千参百四拾伍", 12345, nil},
+		{"3万5千", 35000, nil},
+		{"二〇〇万", 2000000, nil},
+		{"マイナス五", -5, nil},
+		{"九百二十二京", 9220000000000000000, nil},
+		{"千京", 0, ErrKanjiOverflow},
+		{"九二二三三七二〇三六八五四七七五八〇七", 9223372036854775807, nil},
+		{"九二二三三七二〇三六八五四七七五八〇八", 0, ErrKanjiOverflow},
+		{"九千九二二三三七二〇三六八五四七七五八〇七", 0, ErrKanjiNumber},
+		{"十一二", 0, ErrKanjiNumber},
+		{"千二十三", 1023, nil},
+		{"三万十二", 30012, nil},
+		{"", 0, ErrKanjiNumber},
+		{"万", 0, ErrKanjiNumber},
+		{"十百", 0, ErrKanjiNumber},
+		{"万億", 0, ErrKanjiNumber},
+		{"一個", 0, ErrKanjiNumber},
+	}
+	for _, tt := range tests {
+		result, err := ParseKanjiNumber(tt.in)
+		if result != tt.expect || err != tt.err {
+			t.Errorf("%s: expect %d (%v), result %d (%v)", tt.in, tt.expect, tt.err, result, err)
+		}
+	}
+}
+
+func TestFormatKanjiNumber(t *testing.T) {
+	tests := []struct {
+		in     int64
+		style  KanjiStyle
+		expect string
+	}{
+		{0, KanjiMultiplicative, "〇"},
+		{10, KanjiMultiplicative, "十"},
+		{1234, KanjiMultiplicative, "千二百三十四"},
+		{120005000, KanjiMultiplicative, "一億二千万五千"},
+		{-3, KanjiMultiplicative, "マイナス三"},
+		{2024, KanjiPositional, "二〇二四"},
+		{11000, KanjiDaiji, "壱万壱千"},
+		{12345, KanjiDaiji, "壱万弐千参百四拾五"},
+		{-9223372036854775807, KanjiPositional, "マイナス九二二三三七二〇三六八五四七七五八〇七"},
+	}
+	for _, tt := range tests {
+		result := FormatKanjiNumber(tt.in, tt.style)
+		if result != tt.expect {
+			t.Errorf("%d: expect %s, result %s", tt.in, tt.expect, result)
+		}
+		if n, err := ParseKanjiNumber(result); err != nil || n != tt.in {
+			t.Errorf("%s: expect %d, result %d (%v)", result, tt.in, n, err)
+		}
+	}
+}
+
+func TestKanjiNumberMode(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+		opts             []Option
+	}{
+		{"金二千五百円", "j", "金2500円", nil},
+		{"金二千五百円", "jN", "金２５００円", nil},
+		{"令和六年十二月", "j", "令和6年12月", nil},
+		{"壱万円", "j", "10000円", nil},
+		{"千葉県千代田区一丁目", "j", "千葉県千代田区1丁目", nil},
+		{"九十九里浜", "j", "九十九里浜", nil},
+		{"統一", "j", "統一", nil},
+		{"一部の人", "j", "一部の人", nil},
+		{"第三章", "j", "第3章", nil},
+		{"参加者は二百", "j", "参加者は200", nil},
+		{"二千二十四", "j", "2024", nil},
+		{"2500円", "J", "二千五百円", nil},
+		{"２５００円", "J", "二千五百円", nil},
+		{"007", "J", "〇〇七", nil},
+		{"2024年", "J", "二〇二四年", []Option{KanjiNumbers(KanjiPositional)}},
+		{"12345円", "J", "壱万弐千参百四拾五円", []Option{KanjiNumbers(KanjiDaiji)}},
+		{"2500円", "JA", "二千五百円", nil},
+		{"2500円", "A", "２５００円", nil},
+		{"1,000円", "J", "千円", nil},
+		{"１，２３４，５６７円", "J", "百二十三万四千五百六十七円", nil},
+		{"1,2,3", "J", "一,二,三", nil},
+		{"1234,567", "J", "千二百三十四,五百六十七", nil},
+		{"1,0000", "J", "一,〇〇〇〇", nil},
+		{"v1,000", "J", "v1,000", nil},
+		{"3.14", "J", "3.14", nil},
+		{"約3.5kg", "J", "約3.5kg", nil},
+		{"1,000.5", "J", "1,000.5", nil},
+		{"文末1.", "J", "文末一.", nil},
+		{"v2", "J", "v2", nil},
+		{"3D", "J", "3D", nil},
+		{"ｖ２ 2", "J", "ｖ２ 二", nil},
+		{"v2", "JA", "ｖ２", nil},
+		{"第2版", "J", "第二版", nil},
+	}
+	for _, tt := range tests {
+		result := NewConverter(tt.mode, tt.opts...).String(tt.in)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
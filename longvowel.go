@@ -0,0 +1,81 @@
+package kanaco
+
+import "unicode/utf8"
+
+func lowerL(c *character) {
+	if c.filters&FLT_LOWER_L != FLT_LOWER_L {
+		return
+	}
+	c.cval = []byte{}
+	c.drop = true
+}
+
+func upperL(c *character) {
+	if c.filters&FLT_UPPER_L != FLT_UPPER_L {
+		return
+	}
+	base := markBase(c.before)
+	p, _ := foldRune(string(base), FoldWidth|FoldKana)
+	v, ok := kanaVowel(p)
+	if !ok {
+		return
+	}
+	c.cval = []byte(sameScript(string(v), base))
+	c.refeed = true
+}
+
+// longVowel returns the filter for the prolonged sound mark at the head of s
+// when it follows a kana with a vowel, possibly through other marks (カーー):
+// FLT_LOWER_L when it ends the kana word and the l mode is enabled,
+// FLT_UPPER_L when the L mode is enabled.
+func longVowel(c *character, s []byte) int {
+	if c.cv == nil || len(c.before) == 0 || !(c.cv.has('l') || c.cv.has('L')) {
+		return FLT_ASIS
+	}
+	if r, _ := utf8.DecodeRune(s); r != 'ー' && r != 'ｰ' {
+		return FLT_ASIS
+	}
+	p, _ := foldRune(string(markBase(c.before)), FoldWidth|FoldKana)
+	if _, ok := kanaVowel(p); !ok {
+		return FLT_ASIS
+	}
+	if c.cv.has('l') {
+		rest := s
+		for {
+			r, size := utf8.DecodeRune(rest)
+			if r != 'ー' && r != 'ｰ' {
+				break
+			}
+			rest = rest[size:]
+		}
+		if len(rest) > 12 {
+			rest = rest[:12]
+		}
+		if k, _ := foldRune(string(rest), FoldWidth|FoldKana); k < 'ァ' || k > 'ヺ' {
+			return FLT_LOWER_L
+		}
+	}
+	if c.cv.has('L') {
+		return FLT_UPPER_L
+	}
+	return FLT_ASIS
+}
+
+// markBase returns the character before the run of prolonged sound marks at
+// the end of before, a hankaku kana with its voiced sound mark as one.
+func markBase(before []byte) []byte {
+	b := before
+	for {
+		r, n := utf8.DecodeLastRune(b)
+		if r != 'ー' && r != 'ｰ' {
+			break
+		}
+		b = b[:len(b)-n]
+	}
+	r, n := utf8.DecodeLastRune(b)
+	if (r == 0xff9e || r == 0xff9f) && len(b) > n {
+		_, m := utf8.DecodeLastRune(b[:len(b)-n])
+		n += m
+	}
+	return b[len(b)-n:]
+}
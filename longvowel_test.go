@@ -0,0 +1,37 @@
+package kanaco
+
+import "testing"
+
+func TestLongVowel(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+	}{
+		{"コンピューター", "l", "コンピュータ"},
+		{"サーバー、ユーザー。", "l", "サーバ、ユーザ。"},
+		{"ｻｰﾊﾞｰ ﾕｰｻﾞｰ", "l", "ｻｰﾊﾞ ﾕｰｻﾞ"},
+		{"ｻｰﾊﾞｰ", "lK", "サーバ"},
+		{"らーめんやー", "l", "らーめんや"},
+		{"ー", "l", "ー"},
+		{"カー", "L", "カア"},
+		{"コーヒー", "L", "コオヒイ"},
+		{"ｺｰﾋｰ", "L", "ｺｵﾋｲ"},
+		{"ｺｰﾋｰ", "LK", "コオヒイ"},
+		{"すーぱー", "L", "すうぱあ"},
+		{"キャー", "L", "キャア"},
+		{"ンー", "L", "ンー"},
+		{"サーバー", "lL", "サアバ"},
+		{"サーバー", "k", "ｻｰﾊﾞｰ"},
+		{"カーー", "l", "カ"},
+		{"カーー", "L", "カアア"},
+		{"ｶﾞｰｰ", "L", "ｶﾞｱｱ"},
+		{"すごーーい", "L", "すごおおい"},
+		{"すごーーい", "l", "すごーーい"},
+		{"ーー", "L", "ーー"},
+	}
+	for _, tt := range tests {
+		result := String(tt.in, tt.mode)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
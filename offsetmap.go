@@ -0,0 +1,168 @@
+package kanaco
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// OffsetMap maps offsets between an original text and its conversion.
+	// Only the characters whose length changed are recorded; offsets
+	// between them move by the same amount in both texts.
+	OffsetMap struct {
+		segs    []segment
+		runes   [2]int // rune counts of the original and the converted text
+		track   bool   // record changes
+		split   bool   // record a change for each character
+		changes []Change
+	}
+	// Change is a span of the original text that a conversion changes.
+	// Adjacent changed characters make a single Change.
+	Change struct {
+		Start, End int // byte offsets in the original text
+		Before     string
+		After      string
+	}
+	segment struct {
+		orig, conv         int // byte offsets
+		origLen, convLen   int
+		origRune, convRune int // rune offsets
+		origRLen, convRLen int
+	}
+)
+
+func ConvertWithMap(b []byte, mode string) ([]byte, *OffsetMap) {
+	return NewConverter(mode).ConvertWithMap(b)
+}
+
+// ConvertWithMap converts b like Byte and also returns the map between the
+// offsets of b and of the converted bytes.
+func (cv *Converter) ConvertWithMap(b []byte) ([]byte, *OffsetMap) {
+	m := new(OffsetMap)
+	return cv.run(b, cv.filters, m), m
+}
+
+func Changes(b []byte, mode string) []Change {
+	return NewConverter(mode).Changes(b)
+}
+
+// Changes returns the spans of b that the conversion changes.
+func (cv *Converter) Changes(b []byte) []Change {
+	m := &OffsetMap{track: true, changes: []Change{}}
+	cv.run(b, cv.filters, m)
+	return m.changes
+}
+
+// ChangeCounts returns the number of characters of b that each letter of the
+// mode takes part in changing. A letter takes part in a change when the
+// conversion without the letter differs there, so with "eN" both e and N
+// count ⑫. A hankaku kana and its voiced sound mark are a single character.
+func (cv *Converter) ChangeCounts(b []byte) map[byte]int {
+	m := &OffsetMap{track: true, split: true, changes: []Change{}}
+	cv.run(b, cv.filters, m)
+	counts := map[byte]int{}
+	for _, l := range []byte(cv.mode) {
+		if _, ok := counts[l]; ok {
+			continue
+		}
+		counts[l] = 0
+		if len(m.changes) == 0 {
+			continue
+		}
+		out, wm := cv.without(l).ConvertWithMap(b)
+		for _, c := range m.changes {
+			if string(out[wm.ToConverted(c.Start):wm.ToConverted(c.End)]) != c.After {
+				counts[l] += charCount(c.Before)
+			}
+		}
+	}
+	return counts
+}
+
+// without returns a copy of cv without the mode letter l.
+func (cv *Converter) without(l byte) *Converter {
+	w := *cv
+	w.setMode(strings.ReplaceAll(cv.mode, string(l), ""))
+	return &w
+}
+
+// ToOriginal returns the byte offset in the original text corresponding to
+// the byte offset off in the converted text. An offset inside a converted
+// character maps to the start of the original character.
+func (m *OffsetMap) ToOriginal(off int) int {
+	i := sort.Search(len(m.segs), func(i int) bool { return m.segs[i].conv > off }) - 1
+	if i < 0 {
+		return off
+	}
+	s := m.segs[i]
+	if off < s.conv+s.convLen {
+		return s.orig
+	}
+	return s.orig + s.origLen + off - s.conv - s.convLen
+}
+
+// ToConverted returns the byte offset in the converted text corresponding to
+// the byte offset off in the original text. An offset inside an original
+// character maps to the start of its conversion.
+func (m *OffsetMap) ToConverted(off int) int {
+	i := sort.Search(len(m.segs), func(i int) bool { return m.segs[i].orig > off }) - 1
+	if i < 0 {
+		return off
+	}
+	s := m.segs[i]
+	if off < s.orig+s.origLen {
+		return s.conv
+	}
+	return s.conv + s.convLen + off - s.orig - s.origLen
+}
+
+// ToOriginalRune is ToOriginal in rune offsets.
+func (m *OffsetMap) ToOriginalRune(off int) int {
+	i := sort.Search(len(m.segs), func(i int) bool { return m.segs[i].convRune > off }) - 1
+	if i < 0 {
+		return off
+	}
+	s := m.segs[i]
+	if off < s.convRune+s.convRLen {
+		return s.origRune
+	}
+	return s.origRune + s.origRLen + off - s.convRune - s.convRLen
+}
+
+// ToConvertedRune is ToConverted in rune offsets.
+func (m *OffsetMap) ToConvertedRune(off int) int {
+	i := sort.Search(len(m.segs), func(i int) bool { return m.segs[i].origRune > off }) - 1
+	if i < 0 {
+		return off
+	}
+	s := m.segs[i]
+	if off < s.origRune+s.origRLen {
+		return s.convRune
+	}
+	return s.convRune + s.convRLen + off - s.origRune - s.origRLen
+}
+
+// add records the conversion of val at byte offset orig into cval at byte
+// offset conv.
+func (m *OffsetMap) add(orig, conv int, val, cval []byte) {
+	rl, crl := utf8.RuneCount(val), utf8.RuneCount(cval)
+	if len(val) != len(cval) || rl != crl {
+		m.segs = append(m.segs, segment{
+			orig: orig, conv: conv, origLen: len(val), convLen: len(cval),
+			origRune: m.runes[0], convRune: m.runes[1], origRLen: rl, convRLen: crl,
+		})
+	}
+	m.runes[0] += rl
+	m.runes[1] += crl
+	if m.track && !bytes.Equal(val, cval) {
+		if last := len(m.changes) - 1; last >= 0 && m.changes[last].End == orig && !m.split {
+			m.changes[last].End += len(val)
+			m.changes[last].Before += string(val)
+			m.changes[last].After += string(cval)
+		} else {
+			m.changes = append(m.changes, Change{Start: orig, End: orig + len(val), Before: string(val), After: string(cval)})
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package kanaco
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertWithMap(t *testing.T) {
+	in := []byte("ｶﾞｽＡ代ｷﾛ")
+	out, m := ConvertWithMap(in, "Ka")
+	if string(out) != "ガスA代キロ" {
+		t.Fatalf("expect ガスA代キロ, result %s", out)
+	}
+	// original: ｶﾞ 0-6, ｽ 6-9, Ａ 9-12, 代 12-15, ｷ 15-18, ﾛ 18-21
+	// converted: ガ 0-3, ス 3-6, A 6-7, 代 7-10, キ 10-13, ロ 13-16
+	toConv := map[int]int{0: 0, 3: 0, 6: 3, 9: 6, 12: 7, 15: 10, 18: 13, 21: 16}
+	for o, c := range toConv {
+		if r := m.ToConverted(o); r != c {
+			t.Errorf("ToConverted(%d): expect %d, result %d", o, c, r)
+		}
+	}
+	toOrig := map[int]int{0: 0, 3: 6, 6: 9, 7: 12, 10: 15, 13: 18, 16: 21}
+	for c, o := range toOrig {
+		if r := m.ToOriginal(c); r != o {
+			t.Errorf("ToOriginal(%d): expect %d, result %d", c, o, r)
+		}
+	}
+	// runes: original ｶ ﾞ ｽ Ａ 代 ｷ ﾛ, converted ガ ス A 代 キ ロ
+	toConvRune := map[int]int{0: 0, 1: 0, 2: 1, 3: 2, 4: 3, 7: 6}
+	for o, c := range toConvRune {
+		if r := m.ToConvertedRune(o); r != c {
+			t.Errorf("ToConvertedRune(%d): expect %d, result %d", o, c, r)
+		}
+	}
+	toOrigRune := map[int]int{0: 0, 1: 2, 2: 3, 3: 4, 6: 7}
+	for c, o := range toOrigRune {
+		if r := m.ToOriginalRune(c); r != o {
+			t.Errorf("ToOriginalRune(%d): expect %d, result %d", c, o, r)
+		}
+	}
+}
+
+func TestConvertWithMapHighlight(t *testing.T) {
+	in := "東京ｶﾞｽ株式会社"
+	out, m := NewConverter("K").ConvertWithMap([]byte(in))
+	i := strings.Index(string(out), "ガス")
+	start, end := m.ToOriginal(i), m.ToOriginal(i+len("ガス"))
+	if in[start:end] != "ｶﾞｽ" {
+		t.Errorf("expect ｶﾞｽ, result %s", in[start:end])
+	}
+}
+
+func TestChanges(t *testing.T) {
+	changes := Changes([]byte("ｶﾞｽの Ａ代ｷﾛ"), "Ka")
+	expect := []Change{{0, 9, "ｶﾞｽ", "ガス"}, {13, 16, "Ａ", "A"}, {19, 25, "ｷﾛ", "キロ"}}
+	if !reflect.DeepEqual(changes, expect) {
+		t.Errorf("expect %v, result %v", expect, changes)
+	}
+	if changes := Changes([]byte("abc"), "K"); len(changes) != 0 {
+		t.Errorf("expect [], result %v", changes)
+	}
+}
+
+func TestChangeCounts(t *testing.T) {
+	data := []struct {
+		mode   string
+		in     string
+		expect map[byte]int
+	}{
+		{"KVa", "ｶﾞｽＡ", map[byte]int{'K': 2, 'V': 0, 'a': 1}},
+		{"eN", "⑫と3", map[byte]int{'e': 1, 'N': 2}},
+		{"KaK", "ｱＡ", map[byte]int{'K': 1, 'a': 1}},
+		{"K", "abc", map[byte]int{'K': 0}},
+		{"J", "2500円", map[byte]int{'J': 4}},
+	}
+	for _, v := range data {
+		if result := NewConverter(v.mode).ChangeCounts([]byte(v.in)); !reflect.DeepEqual(result, v.expect) {
+			t.Errorf("[%s] %s: expect %v, result %v", v.mode, v.in, v.expect, result)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package kanaco
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// Protector finds the spans of a text that a Converter leaves as they
+	// are. A *regexp.Regexp is a Protector.
+	Protector interface {
+		FindAllIndex(b []byte, n int) [][]int
+	}
+	// submatch is a Protector of the first group of a regular expression.
+	submatch struct {
+		re *regexp.Regexp
+	}
+)
+
+var (
+	// URLs matches http, https and ftp URLs. Punctuation ending a sentence
+	// is not a part of a URL.
+	URLs Protector = regexp.MustCompile(`(?i)\b(?:https?|ftp)://[-A-Za-z0-9._~:/?#\[\]@!$&'()*+,;=%]*[-A-Za-z0-9_~/#=&%+@$*]`)
+	// Emails matches e-mail addresses.
+	Emails Protector = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+`)
+	// Hashtags matches hashtags such as #カナ and ＃カナ that do not follow a
+	// letter or a digit.
+	Hashtags Protector = submatch{regexp.MustCompile(`(?:^|[^\p{L}\p{N}\p{M}_&#＃])([#＃][\p{L}\p{N}\p{M}_]+)`)}
+)
+
+// Protect leaves the spans found by the protectors as they are and converts
+// the rest of the text. Overlapping spans are merged.
+func Protect(ps ...Protector) Option {
+	return func(cv *Converter) {
+		cv.protect = append(cv.protect, ps...)
+	}
+}
+
+// ProtectWords leaves every occurrence of the words as it is. A longer word
+// is preferred to its prefix.
+func ProtectWords(words ...string) Option {
+	ws := []string{}
+	for _, w := range words {
+		if w != "" {
+			ws = append(ws, w)
+		}
+	}
+	if len(ws) == 0 {
+		return func(*Converter) {}
+	}
+	sort.SliceStable(ws, func(i, j int) bool {
+		return len(ws[i]) > len(ws[j])
+	})
+	for i, w := range ws {
+		ws[i] = regexp.QuoteMeta(w)
+	}
+	return Protect(regexp.MustCompile(strings.Join(ws, "|")))
+}
+
+func (s submatch) FindAllIndex(b []byte, n int) [][]int {
+	spans := [][]int{}
+	for _, m := range s.re.FindAllSubmatchIndex(b, n) {
+		spans = append(spans, m[2:4])
+	}
+	return spans
+}
+
+// protected returns the sorted and merged spans of b found by the
+// protectors.
+func (cv *Converter) protected(b []byte) [][]int {
+	if len(cv.protect) == 0 {
+		return nil
+	}
+	spans := [][]int{}
+	for _, p := range cv.protect {
+		for _, s := range p.FindAllIndex(b, -1) {
+			if s[0] < s[1] {
+				spans = append(spans, []int{s[0], s[1]})
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i][0] < spans[j][0]
+	})
+	merged := [][]int{}
+	for _, s := range spans {
+		if last := len(merged) - 1; last >= 0 && s[0] <= merged[last][1] {
+			if s[1] > merged[last][1] {
+				merged[last][1] = s[1]
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
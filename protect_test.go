@@ -0,0 +1,43 @@
+package kanaco
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestProtect(t *testing.T) {
+	data := []struct {
+		mode   string
+		opts   []Option
+		in     string
+		expect string
+	}{
+		{"A", []Option{Protect(URLs)}, "see https://example.com/a?b=1#c.", "ｓｅｅ https://example.com/a?b=1#c．"},
+		{"A", []Option{Protect(URLs)}, "(http://example.com)", "（http://example.com）"},
+		{"A", []Option{Protect(Emails)}, "to: user.name+x@example.co.jp", "ｔｏ： user.name+x@example.co.jp"},
+		{"A", []Option{Protect(URLs, Emails)}, "a@b.jp http://x.jp/a@b.jp", "a@b.jp http://x.jp/a@b.jp"},
+		{"KV", []Option{Protect(Hashtags)}, "ｶﾅ #ｶﾅ ＃ｶﾞｽ C#ｶﾅ", "カナ #ｶﾅ ＃ｶﾞｽ C#カナ"},
+		{"KV", []Option{Protect(Hashtags)}, "#ｱ #ｲ#ｳ", "#ｱ #ｲ#ウ"},
+		{"KV", []Option{ProtectWords("ｺｰﾄﾞ", "ｺｰ", "")}, "ｺｰﾄﾞｺｰﾋｰ", "ｺｰﾄﾞｺｰヒー"},
+		{"KV", []Option{ProtectWords("ﾞ")}, "ｶﾞｽ", "カﾞス"},
+		{"R", []Option{Protect(regexp.MustCompile(`[A-Z]{2}\d+`))}, "ID AB12 ok", "ＩＤ AB12 ｏｋ"},
+		{"eN", []Option{ProtectWords("1")}, "⑫1", "１２1"},
+		{"KV", []Option{ProtectWords()}, "ｱ", "ア"},
+	}
+	for _, v := range data {
+		if result := NewConverter(v.mode, v.opts...).String(v.in); result != v.expect {
+			t.Errorf("[%s] expect %s, result %s", v.in, v.expect, result)
+		}
+	}
+
+	cv := NewConverter("a", Protect(URLs))
+	in := []byte("ａ http://x.jp ｂ")
+	out, m := cv.ConvertWithMap(in)
+	if string(out) != "a http://x.jp b" || m.ToOriginal(len(out)-1) != len(in)-3 {
+		t.Errorf("expect a http://x.jp b, result %s %d", out, m.ToOriginal(len(out)-1))
+	}
+	changes := cv.Changes(in)
+	if len(changes) != 2 || changes[1].Before != "ｂ" {
+		t.Errorf("expect 2 changes, result %v", changes)
+	}
+}
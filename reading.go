@@ -0,0 +1,105 @@
+package kanaco
+
+import "unicode/utf8"
+
+type ReadingRules int
+
+const (
+	ReadingYotsugana ReadingRules = 1 << iota // ヂ -> ジ, ヅ -> ズ
+	ReadingVu                                 // ヴァ -> バ, ヴィ -> ビ, ヴ -> ブ, ヴェ -> ベ, ヴォ -> ボ
+	ReadingWiWe                               // ヰ -> イ, ヱ -> エ
+	ReadingWo                                 // ヲ -> オ
+	ReadingLongVowel                          // オウ, オオ -> オー, ウウ -> ウー
+	ReadingDefault   = ReadingYotsugana | ReadingVu | ReadingWiWe | ReadingWo
+)
+
+var (
+	readings = map[rune]string{
+		'ヂ': "ジ", 'ヅ': "ズ", 'ヴ': "ブ", 'ヰ': "イ", 'ヱ': "エ", 'ヲ': "オ", 'ウ': "ー", 'オ': "ー",
+	}
+	readingsVu = map[rune]string{
+		'ァ': "バ", 'ィ': "ビ", 'ゥ': "ブ", 'ェ': "ベ", 'ォ': "ボ",
+	}
+	toHiragana = &Converter{filters: []filter{lowerC}}
+	toHankaku  = &Converter{filters: []filter{lowerK}}
+)
+
+// Reading sets the rules applied by the y mode. The default is
+// ReadingDefault.
+func Reading(rules ReadingRules) Option {
+	return func(cv *Converter) {
+		cv.reading = rules
+	}
+}
+
+func lowerY(c *character) {
+	if c.filters&FLT_LOWER_Y != FLT_LOWER_Y {
+		return
+	}
+	k, n := foldRune(string(c.val), FoldWidth|FoldKana)
+	v := readings[k]
+	if k == 'ヴ' && n < len(c.val) {
+		small, _ := foldRune(string(c.val[n:]), FoldWidth|FoldKana)
+		v = readingsVu[small]
+	}
+	c.cval = []byte(sameScript(v, c.val))
+	c.refeed = true
+}
+
+// sameScript converts the zenkaku katakana v to hiragana or hankaku when
+// orig starts with hiragana or hankaku.
+func sameScript(v string, orig []byte) string {
+	r, _ := utf8.DecodeRune(orig)
+	if r >= 0x3041 && r <= 0x309f { // ぁ - ゟ
+		return toHiragana.String(v)
+	} else if r >= 0xff61 && r <= 0xff9f { // ｡ - ﾟ
+		return toHankaku.String(v)
+	}
+	return v
+}
+
+// readingLen returns the length of the character at the head of s that the
+// rules of the y mode rewrite, or 0. ヴ and a following small vowel are a
+// single character.
+func readingLen(c *character, s []byte) int {
+	if c.cv == nil || !c.cv.has('y') {
+		return 0
+	}
+	if len(s) > 12 {
+		s = s[:12]
+	}
+	rules := c.cv.reading
+	k, n := foldRune(string(s), FoldWidth|FoldKana)
+	switch k {
+	case 'ヴ':
+		if rules&ReadingVu != ReadingVu {
+			return 0
+		}
+		small, size := foldRune(string(s[n:]), FoldWidth|FoldKana)
+		if _, ok := readingsVu[small]; ok {
+			n += size
+		}
+		return n
+	case 'ヂ', 'ヅ':
+		if rules&ReadingYotsugana == ReadingYotsugana {
+			return n
+		}
+	case 'ヰ', 'ヱ':
+		if rules&ReadingWiWe == ReadingWiWe {
+			return n
+		}
+	case 'ヲ':
+		if rules&ReadingWo == ReadingWo {
+			return n
+		}
+	case 'ウ', 'オ':
+		if rules&ReadingLongVowel != ReadingLongVowel || len(c.prev) == 0 {
+			return 0
+		}
+		p, _ := foldRune(string(c.prev), FoldWidth|FoldKana)
+		if v, ok := kanaVowel(p); ok && (v == 'オ' || (v == 'ウ' && k == 'ウ')) {
+			return n
+		}
+	}
+	return 0
+}
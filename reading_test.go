@@ -0,0 +1,36 @@
+package kanaco
+
+import "testing"
+
+func TestReading(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+		opts             []Option
+	}{
+		{"ハナヂ ツヅキ", "y", "ハナジ ツズキ", nil},
+		{"はなぢ", "y", "はなじ", nil},
+		{"ﾊﾅﾁﾞ", "y", "ﾊﾅｼﾞ", nil},
+		{"ﾊﾅﾁﾞ", "yK", "ハナジ", nil},
+		{"ﾊﾅﾁﾞ", "Ky", "ハナジ", nil},
+		{"はなぢ", "yC", "ハナジ", nil},
+		{"ヴァイオリン", "y", "バイオリン", nil},
+		{"ｳﾞｧｲｵﾘﾝ", "yK", "バイオリン", nil},
+		{"ヴィーナス ヴェール ヴォーカル ヴ", "y", "ビーナス ベール ボーカル ブ", nil},
+		{"ヴァイオリン", "yc", "ばいおりん", nil},
+		{"ヰスキー ヱビス ヲタク", "y", "イスキー エビス オタク", nil},
+		{"ヲタク", "y", "ヲタク", []Option{Reading(ReadingYotsugana)}},
+		{"ヴァ", "y", "ヴァ", []Option{Reading(ReadingYotsugana)}},
+		{"オオタ サトウ ユウキ", "y", "オオタ サトウ ユウキ", nil},
+		{"オオタ サトウ ユウキ トオル", "y", "オータ サトー ユーキ トール", []Option{Reading(ReadingLongVowel)}},
+		{"きょうこ", "y", "きょーこ", []Option{Reading(ReadingLongVowel)}},
+		{"ｻﾄｳ", "y", "ｻﾄｰ", []Option{Reading(ReadingLongVowel)}},
+		{"アウト", "y", "アウト", []Option{Reading(ReadingLongVowel)}},
+		{"ハナヂ", "C", "ハナヂ", nil},
+	}
+	for _, tt := range tests {
+		result := NewConverter(tt.mode, tt.opts...).String(tt.in)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package record reads and writes fixed-length records in Shift_JIS, the
+// layout of many Japanese mainframe and bank transfer files. It is apart
+// from kanaco so that the core package has no dependencies.
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/elfincafe/kanaco"
+	"golang.org/x/text/encoding/japanese"
+)
+
+type (
+	// Field is a fixed-length field of a record, measured in Shift_JIS bytes
+	// (hankaku = 1, zenkaku = 2).
+	Field struct {
+		Width int    // length in Shift_JIS bytes
+		Mode  string // mode applied before the value is fitted, e.g. "ka"
+		Right bool   // align to the right, e.g. for numbers
+		Pad   byte   // padding byte, a space when 0
+	}
+	// Writer writes fixed-length records in Shift_JIS.
+	Writer struct {
+		Newline string // written after each record, "\r\n" by default
+		w       io.Writer
+		fields  []Field
+		cvs     []*kanaco.Converter
+	}
+	// Reader reads fixed-length records in Shift_JIS into UTF-8 fields.
+	Reader struct {
+		r      *bufio.Reader
+		fields []Field
+		length int
+	}
+)
+
+var (
+	ErrFields   = errors.New("record: wrong number of fields")
+	ErrLength   = errors.New("record: short record")
+	ErrShiftJIS = errors.New("record: character not in Shift_JIS")
+)
+
+// Format converts each value with the mode of its field and fits it to
+// the width of the field, truncating or padding it without splitting a
+// character or a hankaku kana from its voiced sound mark.
+func Format(fields []Field, values []string) ([]byte, error) {
+	return formatRecord(fields, converters(fields), values)
+}
+
+// Parse splits a record into fields and decodes them into UTF-8,
+// trimming the padding.
+func Parse(fields []Field, record []byte) ([]string, error) {
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if len(record) < f.Width {
+			return nil, ErrLength
+		}
+		v, err := japanese.ShiftJIS.NewDecoder().Bytes(trimPad(record[:f.Width], f))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, string(v))
+		record = record[f.Width:]
+	}
+	return values, nil
+}
+
+func NewWriter(w io.Writer, fields []Field) *Writer {
+	return &Writer{Newline: "\r\n", w: w, fields: fields, cvs: converters(fields)}
+}
+
+// Write writes a record of values followed by Newline.
+func (rw *Writer) Write(values []string) error {
+	record, err := formatRecord(rw.fields, rw.cvs, values)
+	if err != nil {
+		return err
+	}
+	record = append(record, rw.Newline...)
+	_, err = rw.w.Write(record)
+	return err
+}
+
+func NewReader(r io.Reader, fields []Field) *Reader {
+	rr := &Reader{r: bufio.NewReader(r), fields: fields}
+	for _, f := range fields {
+		rr.length += f.Width
+	}
+	return rr
+}
+
+// Read reads the next record. Line breaks between records are skipped. It
+// returns io.EOF when there are no more records.
+func (rr *Reader) Read() ([]string, error) {
+	for {
+		b, err := rr.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != '\r' && b != '\n' {
+			rr.r.UnreadByte()
+			break
+		}
+	}
+	record := make([]byte, rr.length)
+	if _, err := io.ReadFull(rr.r, record); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = ErrLength
+		}
+		return nil, err
+	}
+	return Parse(rr.fields, record)
+}
+
+func converters(fields []Field) []*kanaco.Converter {
+	cvs := make([]*kanaco.Converter, len(fields))
+	for i, f := range fields {
+		if f.Mode != "" {
+			cvs[i] = kanaco.NewConverter(f.Mode)
+		}
+	}
+	return cvs
+}
+
+func formatRecord(fields []Field, cvs []*kanaco.Converter, values []string) ([]byte, error) {
+	if len(values) != len(fields) {
+		return nil, ErrFields
+	}
+	record := []byte{}
+	for i, f := range fields {
+		v := values[i]
+		if cvs[i] != nil {
+			v = cvs[i].String(v)
+		}
+		b, err := fitShiftJIS(v, f)
+		if err != nil {
+			return nil, err
+		}
+		record = append(record, b...)
+	}
+	return record, nil
+}
+
+// fitShiftJIS encodes s in Shift_JIS and truncates or pads it to the width of
+// f.
+func fitShiftJIS(s string, f Field) ([]byte, error) {
+	enc := japanese.ShiftJIS.NewEncoder()
+	b := make([]byte, 0, f.Width)
+	for i := 0; i < len(s); {
+		n := cellLen(s[i:])
+		if r, _ := utf8.DecodeRuneInString(s[i:]); r == utf8.RuneError && n == 1 {
+			return nil, ErrShiftJIS
+		}
+		cell, err := enc.String(s[i : i+n])
+		if err != nil {
+			return nil, ErrShiftJIS
+		}
+		if len(b)+len(cell) > f.Width {
+			break
+		}
+		b = append(b, cell...)
+		i += n
+	}
+	pad := bytes.Repeat([]byte{padByte(f)}, f.Width-len(b))
+	if f.Right {
+		return append(pad, b...), nil
+	}
+	return append(b, pad...), nil
+}
+
+// cellLen returns the length of the character at the head of s, taking a
+// hankaku kana and its voiced sound mark (ｶﾞ) as one character.
+func cellLen(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r >= 0xff66 && r <= 0xff9d { // ｦ - ﾝ
+		if m, size := utf8.DecodeRuneInString(s[n:]); m == 0xff9e || m == 0xff9f {
+			return n + size
+		}
+	}
+	return n
+}
+
+// trimPad removes the padding of a field. A right-aligned field of padding
+// only keeps a pad character, so that 0 padded with '0' is still 0.
+func trimPad(b []byte, f Field) []byte {
+	if f.Right {
+		if v := bytes.TrimLeft(b, string(padByte(f))); len(v) > 0 || len(b) == 0 {
+			return v
+		}
+		return b[len(b)-1:]
+	}
+	return bytes.TrimRight(b, string(padByte(f)))
+}
+
+func padByte(f Field) byte {
+	if f.Pad == 0 {
+		return ' '
+	}
+	return f.Pad
+}
@@ -0,0 +1,75 @@
+package record
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func sjis(s string) []byte {
+	b, _ := japanese.ShiftJIS.NewEncoder().String(s)
+	return []byte(b)
+}
+
+func TestFormat(t *testing.T) {
+	fields := []Field{{Width: 6, Mode: "ka"}, {Width: 5, Right: true, Pad: '0', Mode: "a"}, {Width: 5}}
+	data := []struct {
+		values []string
+		expect string
+	}{
+		{[]string{"ガス", "１２", "東京"}, "ｶﾞｽ   00012東京 "},
+		{[]string{"ガステーブル", "123456", "東京都"}, "ｶﾞｽﾃｰ 12345東京 "},
+		{[]string{"カガ", "", "a東"}, "ｶｶﾞ   00000a東  "},
+	}
+	for _, v := range data {
+		result, err := Format(fields, v.values)
+		if err != nil || !bytes.Equal(result, sjis(v.expect)) {
+			t.Errorf("%v: expect %q, result %q %v", v.values, sjis(v.expect), result, err)
+		}
+	}
+	for _, v := range []string{"0", "10", "100"} {
+		record, _ := Format(fields, []string{"ｱ", v, ""})
+		if values, err := Parse(fields, record); err != nil || values[1] != v {
+			t.Errorf("%s: expect a round trip, result %v %v", v, values, err)
+		}
+	}
+	if _, err := Format(fields, []string{"a"}); err != ErrFields {
+		t.Errorf("expect %v, result %v", ErrFields, err)
+	}
+	if _, err := Format(fields, []string{"a", "1", "😀"}); err != ErrShiftJIS {
+		t.Errorf("expect %v, result %v", ErrShiftJIS, err)
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	fields := []Field{{Width: 6, Mode: "ka"}, {Width: 4, Right: true, Pad: '0'}}
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, fields)
+	for _, values := range [][]string{{"ガス", "12"}, {"ヤマダ", "345"}} {
+		if err := w.Write(values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expect := sjis("ｶﾞｽ   0012\r\nﾔﾏﾀﾞ  0345\r\n"); !bytes.Equal(buf.Bytes(), expect) {
+		t.Errorf("expect %q, result %q", expect, buf.Bytes())
+	}
+	buf.Write(sjis("ｶﾅ"))
+
+	r := NewReader(buf, fields)
+	expect := [][]string{{"ｶﾞｽ", "12"}, {"ﾔﾏﾀﾞ", "345"}}
+	for _, e := range expect {
+		values, err := r.Read()
+		if err != nil || !reflect.DeepEqual(values, e) {
+			t.Errorf("expect %v, result %v %v", e, values, err)
+		}
+	}
+	if _, err := r.Read(); err != ErrLength {
+		t.Errorf("expect %v, result %v", ErrLength, err)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("expect %v, result %v", io.EOF, err)
+	}
+}
@@ -0,0 +1,266 @@
+package kanaco
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	variantsMu sync.Mutex
+	variants   = map[FoldOptions]map[rune][]string{}
+)
+
+// maxFoldedClass is the size of the largest class folded as literals.
+const maxFoldedClass = 256
+
+// voicedMarks matches the marks ignored by FoldVoiced: ゛ ゜ ﾞ ﾟ and the
+// combining voiced sound marks.
+var voicedMarks = &syntax.Regexp{Op: syntax.OpCharClass, Rune: []rune{0x3099, 0x309c, 0xff9e, 0xff9f}}
+
+// CompileRegexp compiles a regular expression whose literal characters match
+// every variant that is equal under the given foldings, e.g. "ガ.*ス" matches
+// "ｶﾞｽ", and "がす" with FoldKana. Character classes written in the pattern
+// ([ア-オ], \pL) are left as they are.
+func CompileRegexp(pattern string, fold FoldOptions) (*regexp.Regexp, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(foldRegexp(re, fold, variantsOf(fold), explicitClasses(pattern)).String())
+}
+
+// foldRegexp rewrites the literals of re. The parser turns alternations of
+// single characters (ガ|ギ) into character classes, so the classes not in
+// explicit are folded as literals too.
+func foldRegexp(re *syntax.Regexp, fold FoldOptions, vars map[rune][]string, explicit map[string]bool) *syntax.Regexp {
+	switch re.Op {
+	case syntax.OpLiteral:
+	case syntax.OpCharClass:
+		if explicit[string(re.Rune)] || classSize(re.Rune) > maxFoldedClass {
+			return re
+		}
+		alt := &syntax.Regexp{Op: syntax.OpAlternate}
+		for i := 0; i < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				alt.Sub = append(alt.Sub, foldRegexp(literal(string(r), re.Flags), fold, vars, explicit))
+			}
+		}
+		return alt
+	case syntax.OpConcat:
+		re.Sub = joinVoiced(re.Sub)
+		fallthrough
+	default:
+		for i, sub := range re.Sub {
+			re.Sub[i] = foldRegexp(sub, fold, vars, explicit)
+		}
+		return re
+	}
+	flags := re.Flags
+	if fold&FoldCase == FoldCase {
+		flags |= syntax.FoldCase
+	}
+	subs := []*syntax.Regexp{}
+	for i := 0; i < len(re.Rune); i++ {
+		s := string(re.Rune[i])
+		// a hankaku kana and its voiced sound mark are a single character
+		if i+1 < len(re.Rune) && (re.Rune[i+1] == 0xff9e || re.Rune[i+1] == 0xff9f) {
+			if _, n := foldRune(s+string(re.Rune[i+1]), fold); n > len(s) {
+				s += string(re.Rune[i+1])
+				i++
+			}
+		}
+		key, _ := foldRune(s, fold)
+		if key < 0 {
+			continue
+		}
+		vs, ok := vars[key]
+		if !ok {
+			subs = append(subs, literal(s, flags))
+			continue
+		}
+		alt := &syntax.Regexp{Op: syntax.OpAlternate}
+		for _, v := range vs {
+			alt.Sub = append(alt.Sub, literal(v, flags))
+		}
+		subs = append(subs, alt)
+		if fold&FoldVoiced == FoldVoiced && isKanaKey(key) {
+			subs = append(subs, &syntax.Regexp{Op: syntax.OpStar, Sub: []*syntax.Regexp{voicedMarks}})
+		}
+	}
+	if len(subs) == 1 {
+		return subs[0]
+	}
+	return &syntax.Regexp{Op: syntax.OpConcat, Sub: subs, Flags: re.Flags}
+}
+
+// joinVoiced keeps a hankaku kana and the voiced sound marks the parser split
+// from it together: ｶﾞ+ repeats ｶﾞ, and ｶ(?:ﾞ|ﾟ) from ｶﾞ|ｶﾟ is ｶﾞ|ｶﾟ.
+func joinVoiced(subs []*syntax.Regexp) []*syntax.Regexp {
+	out := []*syntax.Regexp{}
+	for k := 0; k < len(subs); k++ {
+		a := subs[k]
+		if a.Op != syntax.OpLiteral || len(a.Rune) == 0 || k+1 >= len(subs) {
+			out = append(out, a)
+			continue
+		}
+		last := string(a.Rune[len(a.Rune)-1])
+		b := subs[k+1]
+		var joined *syntax.Regexp
+		switch b.Op {
+		case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+			if m := b.Sub[0]; m.Op == syntax.OpLiteral && len(m.Rune) == 1 && isVoicedPair(last, m.Rune[0]) {
+				joined = &syntax.Regexp{Op: b.Op, Flags: b.Flags, Min: b.Min, Max: b.Max,
+					Sub: []*syntax.Regexp{literal(last+string(m.Rune), m.Flags)}}
+			}
+		case syntax.OpCharClass:
+			if len(b.Rune) == 2 && b.Rune[0] >= 0xff9e && b.Rune[1] <= 0xff9f && isVoicedPair(last, b.Rune[0]) {
+				joined = &syntax.Regexp{Op: syntax.OpAlternate}
+				for r := b.Rune[0]; r <= b.Rune[1]; r++ {
+					joined.Sub = append(joined.Sub, literal(last+string(r), b.Flags))
+				}
+			}
+		}
+		if joined == nil {
+			out = append(out, a)
+			continue
+		}
+		if len(a.Rune) > 1 {
+			out = append(out, literal(string(a.Rune[:len(a.Rune)-1]), a.Flags))
+		}
+		out = append(out, joined)
+		k++
+	}
+	return out
+}
+
+func isVoicedPair(s string, mark rune) bool {
+	_, n := foldRune(s+string(mark), 0)
+	return n > len(s)
+}
+
+// explicitClasses returns the character classes written in pattern, keyed
+// by their ranges.
+func explicitClasses(pattern string) map[string]bool {
+	set := map[string]bool{}
+	add := func(tok string) {
+		for _, flags := range []syntax.Flags{syntax.Perl, syntax.Perl | syntax.FoldCase} {
+			if re, err := syntax.Parse(tok, flags); err == nil && re.Op == syntax.OpCharClass {
+				set[string(re.Rune)] = true
+			}
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i+1 >= len(pattern) {
+				break
+			}
+			j := i + 2
+			switch pattern[i+1] {
+			case 'd', 'D', 's', 'S', 'w', 'W':
+			case 'p', 'P':
+				if j < len(pattern) && pattern[j] == '{' {
+					if k := strings.IndexByte(pattern[j:], '}'); k >= 0 {
+						j += k + 1
+					}
+				} else if j < len(pattern) {
+					_, n := utf8.DecodeRuneInString(pattern[j:])
+					j += n
+				}
+			default:
+				i++
+				continue
+			}
+			add(pattern[i:j])
+			i = j - 1
+		case '[':
+			j := classEnd(pattern, i)
+			add(pattern[i:j])
+			i = j - 1
+		}
+	}
+	return set
+}
+
+// classEnd returns the index after the bracketed class starting at i.
+func classEnd(pattern string, i int) int {
+	j := i + 1
+	if j < len(pattern) && pattern[j] == '^' {
+		j++
+	}
+	if j < len(pattern) && pattern[j] == ']' {
+		j++
+	}
+	for ; j < len(pattern); j++ {
+		switch {
+		case pattern[j] == '\\':
+			j++
+		case strings.HasPrefix(pattern[j:], "[:"):
+			if k := strings.Index(pattern[j:], ":]"); k >= 0 {
+				j += k + 1
+			}
+		case pattern[j] == ']':
+			return j + 1
+		}
+	}
+	return len(pattern)
+}
+
+func classSize(ranges []rune) int {
+	n := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		n += int(ranges[i+1]-ranges[i]) + 1
+	}
+	return n
+}
+
+func literal(s string, flags syntax.Flags) *syntax.Regexp {
+	return &syntax.Regexp{Op: syntax.OpLiteral, Rune: []rune(s), Flags: flags}
+}
+
+func isKanaKey(key rune) bool {
+	key &^= narrow
+	return (key >= 0x3041 && key <= 0x309f) || (key >= 0x30a1 && key <= 0x30ff)
+}
+
+// variantsOf returns the characters grouped by their folded key, longest
+// first so that ｶﾞ is preferred to ｶ.
+func variantsOf(fold FoldOptions) map[rune][]string {
+	variantsMu.Lock()
+	defer variantsMu.Unlock()
+	if vars, ok := variants[fold]; ok {
+		return vars
+	}
+	candidates := []string{}
+	for _, rng := range [][2]rune{{0x20, 0x7e}, {0x3000, 0x3000}, {0x3041, 0x3096}, {0x309b, 0x309e}, {0x30a1, 0x30fe}, {0xff01, 0xff5e}, {0xff61, 0xff9f}} {
+		for r := rng[0]; r <= rng[1]; r++ {
+			candidates = append(candidates, string(r))
+			if r >= 0xff61 {
+				candidates = append(candidates, string(r)+"ﾞ", string(r)+"ﾟ")
+			}
+		}
+	}
+	vars := map[rune][]string{}
+	for _, s := range candidates {
+		key, n := foldRune(s, fold)
+		if key < 0 || n != len(s) {
+			continue
+		}
+		vars[key] = append(vars[key], s)
+	}
+	for key, vs := range vars {
+		if len(vs) == 1 {
+			delete(vars, key)
+			continue
+		}
+		sort.SliceStable(vs, func(i, j int) bool {
+			return utf8.RuneCountInString(vs[i]) > utf8.RuneCountInString(vs[j])
+		})
+	}
+	variants[fold] = vars
+	return vars
+}
@@ -0,0 +1,47 @@
+package kanaco
+
+import "testing"
+
+func TestCompileRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		fold    FoldOptions
+		in      string
+		expect  string
+	}{
+		{"ガ.*ス", FoldWidth | FoldKana, "東京ｶﾞｽ", "ｶﾞｽ"},
+		{"ガ.*ス", FoldWidth | FoldKana, "がす", "がす"},
+		{"ガ.*ス", FoldWidth | FoldKana, "ｶﾞス", "ｶﾞス"},
+		{"ガ.*ス", FoldWidth | FoldKana, "ｶｽ", ""},
+		{"ガ.*ス", FoldAll, "ｶｽ", "ｶｽ"},
+		{"が", FoldAll, "か゛", "か゛"},
+		{"ｶﾞｽ", FoldWidth, "ガス", "ガス"},
+		{"abc", FoldWidth | FoldCase, "ＡＢＣ", "ＡＢＣ"},
+		{"ab+c", FoldWidth, "ａｂｂｂc", "ａｂｂｂc"},
+		{"(?i)ｋａｎａ", FoldWidth, "KanaCo", "Kana"},
+		{"[ア-オ]", FoldWidth, "ｱ", ""},
+		{"データ|ベース", FoldWidth, "ﾍﾞｰｽ", "ﾍﾞｰｽ"},
+		{"ガ|ギ", FoldWidth, "ｷﾞｶﾞ", "ｷﾞ"},
+		{"ガ|ギ", FoldWidth, "ｶ", ""},
+		{"x(?:ア|イ)", FoldWidth | FoldKana, "xい", "xい"},
+		{"ｶﾞ+", FoldWidth, "ｶﾞガｶﾞｽ", "ｶﾞガｶﾞ"},
+		{"ｱｶﾞ*ｽ", FoldWidth, "アｽ", "アｽ"},
+		{"ｶﾞ|ｶﾟ", 0, "ｶﾟ", "ｶﾟ"},
+		{"ｶﾞ|ｶﾟ", FoldWidth, "ガ", "ガ"},
+		{"[アイ]", FoldWidth, "ｱ", ""},
+		{`\d|ア`, FoldWidth, "ｱ1", "ｱ"},
+	}
+	for _, tt := range tests {
+		re, err := CompileRegexp(tt.pattern, tt.fold)
+		if err != nil {
+			t.Errorf("%s: %s", tt.pattern, err.Error())
+			continue
+		}
+		if result := re.FindString(tt.in); result != tt.expect {
+			t.Errorf("%s (%s): expect %s, result %s", tt.pattern, tt.in, tt.expect, result)
+		}
+	}
+	if _, err := CompileRegexp("ガ(", FoldAll); err == nil {
+		t.Error("expect error")
+	}
+}
@@ -0,0 +1,97 @@
+package kanaco
+
+import "bytes"
+
+// romans maps the Roman numerals of U+2160 - U+217F to ASCII letters. The
+// NEC and IBM rows of CP932 decode to the same code points.
+var romans = map[string]string{
+	"Ⅰ": "I", "Ⅱ": "II", "Ⅲ": "III", "Ⅳ": "IV", "Ⅴ": "V", "Ⅵ": "VI", "Ⅶ": "VII", "Ⅷ": "VIII",
+	"Ⅸ": "IX", "Ⅹ": "X", "Ⅺ": "XI", "Ⅻ": "XII", "Ⅼ": "L", "Ⅽ": "C", "Ⅾ": "D", "Ⅿ": "M",
+	"ⅰ": "i", "ⅱ": "ii", "ⅲ": "iii", "ⅳ": "iv", "ⅴ": "v", "ⅵ": "vi", "ⅶ": "vii", "ⅷ": "viii",
+	"ⅸ": "ix", "ⅹ": "x", "ⅺ": "xi", "ⅻ": "xii", "ⅼ": "l", "ⅽ": "c", "ⅾ": "d", "ⅿ": "m",
+}
+
+// romanNumerals maps the ASCII spellings of one to twelve to a single
+// Roman numeral. L, C, D and M are left alone since they are ordinary
+// words far more often than numerals.
+var romanNumerals = map[string]string{
+	"I": "Ⅰ", "II": "Ⅱ", "III": "Ⅲ", "IV": "Ⅳ", "V": "Ⅴ", "VI": "Ⅵ",
+	"VII": "Ⅶ", "VIII": "Ⅷ", "IX": "Ⅸ", "X": "Ⅹ", "XI": "Ⅺ", "XII": "Ⅻ",
+	"i": "ⅰ", "ii": "ⅱ", "iii": "ⅲ", "iv": "ⅳ", "v": "ⅴ", "vi": "ⅵ",
+	"vii": "ⅶ", "viii": "ⅷ", "ix": "ⅸ", "x": "ⅹ", "xi": "ⅺ", "xii": "ⅻ",
+}
+
+func lowerM(c *character) {
+	if c.filters&FLT_LOWER_M != FLT_LOWER_M {
+		return
+	}
+	v, ok := romans[string(c.val)]
+	if !ok {
+		return
+	}
+	c.cval = []byte(v)
+	c.refeed = true
+}
+
+func upperM(c *character) {
+	if c.filters&FLT_UPPER_M != FLT_UPPER_M {
+		return
+	}
+	c.cval = []byte(romanNumerals[string(c.val)])
+}
+
+// romanNumeralBefore and romanNumeralAfter are the words around a single
+// letter I, V or X that make it a numeral rather than a pronoun or a sign.
+var (
+	romanNumeralBefore = []string{"第", "part", "chapter", "vol.", "no."}
+	romanNumeralAfter  = []string{"章", "巻", "部", "編"}
+)
+
+// romanLen returns the length of the Roman numeral at the head of s when the
+// M mode is enabled. The numeral must be a whole word: it may neither follow
+// nor be followed by another ASCII letter. A single letter (I, x) is
+// converted only after 第 or Part, or before 章 or 巻 (Part I, 第V章).
+func romanLen(c *character, s []byte) int {
+	if c.cv == nil || !c.cv.has('M') {
+		return 0
+	}
+	if len(c.prev) == 1 && isLetter(c.prev[0]) {
+		return 0
+	}
+	n := 0
+	for n < len(s) && isLetter(s[n]) {
+		n++
+	}
+	if _, ok := romanNumerals[string(s[:n])]; !ok {
+		return 0
+	}
+	if n == 1 && !romanContext(c.before, s[n:]) {
+		return 0
+	}
+	return n
+}
+
+// romanContext reports whether the text before and after a single letter
+// numeral marks it as a numeral.
+func romanContext(before, after []byte) bool {
+	for _, w := range romanNumeralAfter {
+		if bytes.HasPrefix(after, []byte(w)) {
+			return true
+		}
+	}
+	before = bytes.TrimRight(before, " ")
+	if len(before) > len("chapter") {
+		before = before[len(before)-len("chapter"):]
+	}
+	before = bytes.ToLower(before)
+	for _, w := range romanNumeralBefore {
+		if bytes.HasSuffix(before, []byte(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetter(b byte) bool {
+	return (b >= 0x41 && b <= 0x5a) || (b >= 0x61 && b <= 0x7a)
+}
@@ -0,0 +1,36 @@
+package kanaco
+
+import "testing"
+
+func TestRoman(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+	}{
+		{"第Ⅳ章", "m", "第IV章"},
+		{"第Ⅳ章", "mR", "第ＩＶ章"},
+		{"ⅻ時", "m", "xii時"},
+		{"Ⅿ", "m", "M"},
+		{"第IV章", "M", "第Ⅳ章"},
+		{"Part II", "M", "Part Ⅱ"},
+		{"vol.xi", "M", "vol.ⅺ"},
+		{"MIX XIV IIII", "M", "MIX XIV IIII"},
+		{"Ⅻ", "M", "Ⅻ"},
+		{"IV", "MR", "Ⅳ"},
+		{"IV CD", "MR", "Ⅳ ＣＤ"},
+		{"IV", "R", "ＩＶ"},
+		{"Part I", "M", "Part Ⅰ"},
+		{"CHAPTER  V", "M", "CHAPTER  Ⅴ"},
+		{"第X章", "M", "第Ⅹ章"},
+		{"I巻", "M", "Ⅰ巻"},
+		{"I am here", "M", "I am here"},
+		{"3 x 4", "M", "3 x 4"},
+		{"size: v", "M", "size: v"},
+		{"(i) x", "M", "(i) x"},
+	}
+	for _, tt := range tests {
+		result := String(tt.in, tt.mode)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package kanaco
+
+// squared maps CJK compatibility squares (squared katakana words, era names
+// and unit symbols) to their compatibility decomposition.
+var squared = map[string]string{
+	"㋿": "令和", "㌀": "アパート", "㌁": "アルファ", "㌂": "アンペア", "㌃": "アール", "㌄": "イニング",
+	"㌅": "インチ", "㌆": "ウォン", "㌇": "エスクード", "㌈": "エーカー", "㌉": "オンス", "㌊": "オーム",
+	"㌋": "カイリ", "㌌": "カラット", "㌍": "カロリー", "㌎": "ガロン", "㌏": "ガンマ", "㌐": "ギガ",
+	"㌑": "ギニー", "㌒": "キュリー", "㌓": "ギルダー", "㌔": "キロ", "㌕": "キログラム", "㌖": "キロメートル",
+	"㌗": "キロワット", "㌘": "グラム", "㌙": "グラムトン", "㌚": "クルゼイロ", "㌛": "クローネ", "㌜": "ケース",
+	"㌝": "コルナ", "㌞": "コーポ", "㌟": "サイクル", "㌠": "サンチーム", "㌡": "シリング", "㌢": "センチ",
+	"㌣": "セント", "㌤": "ダース", "㌥": "デシ", "㌦": "ドル", "㌧": "トン", "㌨": "ナノ",
+	"㌩": "ノット", "㌪": "ハイツ", "㌫": "パーセント", "㌬": "パーツ", "㌭": "バーレル", "㌮": "ピアストル",
+	"㌯": "ピクル", "㌰": "ピコ", "㌱": "ビル", "㌲": "ファラッド", "㌳": "フィート", "㌴": "ブッシェル",
+	"㌵": "フラン", "㌶": "ヘクタール", "㌷": "ペソ", "㌸": "ペニヒ", "㌹": "ヘルツ", "㌺": "ペンス",
+	"㌻": "ページ", "㌼": "ベータ", "㌽": "ポイント", "㌾": "ボルト", "㌿": "ホン", "㍀": "ポンド",
+	"㍁": "ホール", "㍂": "ホーン", "㍃": "マイクロ", "㍄": "マイル", "㍅": "マッハ", "㍆": "マルク",
+	"㍇": "マンション", "㍈": "ミクロン", "㍉": "ミリ", "㍊": "ミリバール", "㍋": "メガ", "㍌": "メガトン",
+	"㍍": "メートル", "㍎": "ヤード", "㍏": "ヤール", "㍐": "ユアン", "㍑": "リットル", "㍒": "リラ",
+	"㍓": "ルピー", "㍔": "ルーブル", "㍕": "レム", "㍖": "レントゲン", "㍗": "ワット", "㍘": "0点",
+	"㍙": "1点", "㍚": "2点", "㍛": "3点", "㍜": "4点", "㍝": "5点", "㍞": "6点",
+	"㍟": "7点", "㍠": "8点", "㍡": "9点", "㍢": "10点", "㍣": "11点", "㍤": "12点",
+	"㍥": "13点", "㍦": "14点", "㍧": "15点", "㍨": "16点", "㍩": "17点", "㍪": "18点",
+	"㍫": "19点", "㍬": "20点", "㍭": "21点", "㍮": "22点", "㍯": "23点", "㍰": "24点",
+	"㍱": "hPa", "㍲": "da", "㍳": "AU", "㍴": "bar", "㍵": "oV", "㍶": "pc",
+	"㍷": "dm", "㍸": "dm2", "㍹": "dm3", "㍺": "IU", "㍻": "平成", "㍼": "昭和",
+	"㍽": "大正", "㍾": "明治", "㍿": "株式会社", "㎀": "pA", "㎁": "nA", "㎂": "μA",
+	"㎃": "mA", "㎄": "kA", "㎅": "KB", "㎆": "MB", "㎇": "GB", "㎈": "cal",
+	"㎉": "kcal", "㎊": "pF", "㎋": "nF", "㎌": "μF", "㎍": "μg", "㎎": "mg",
+	"㎏": "kg", "㎐": "Hz", "㎑": "kHz", "㎒": "MHz", "㎓": "GHz", "㎔": "THz",
+	"㎕": "μl", "㎖": "ml", "㎗": "dl", "㎘": "kl", "㎙": "fm", "㎚": "nm",
+	"㎛": "μm", "㎜": "mm", "㎝": "cm", "㎞": "km", "㎟": "mm2", "㎠": "cm2",
+	"㎡": "m2", "㎢": "km2", "㎣": "mm3", "㎤": "cm3", "㎥": "m3", "㎦": "km3",
+	"㎧": "m∕s", "㎨": "m∕s2", "㎩": "Pa", "㎪": "kPa", "㎫": "MPa", "㎬": "GPa",
+	"㎭": "rad", "㎮": "rad∕s", "㎯": "rad∕s2", "㎰": "ps", "㎱": "ns", "㎲": "μs",
+	"㎳": "ms", "㎴": "pV", "㎵": "nV", "㎶": "μV", "㎷": "mV", "㎸": "kV",
+	"㎹": "MV", "㎺": "pW", "㎻": "nW", "㎼": "μW", "㎽": "mW", "㎾": "kW",
+	"㎿": "MW", "㏀": "kΩ", "㏁": "MΩ", "㏂": "a.m.", "㏃": "Bq", "㏄": "cc",
+	"㏅": "cd", "㏆": "C∕kg", "㏇": "Co.", "㏈": "dB", "㏉": "Gy", "㏊": "ha",
+	"㏋": "HP", "㏌": "in", "㏍": "KK", "㏎": "KM", "㏏": "kt", "㏐": "lm",
+	"㏑": "ln", "㏒": "log", "㏓": "lx", "㏔": "mb", "㏕": "mil", "㏖": "mol",
+	"㏗": "PH", "㏘": "p.m.", "㏙": "PPM", "㏚": "PR", "㏛": "sr", "㏜": "Sv",
+	"㏝": "Wb", "㏞": "V∕m", "㏟": "A∕m", "㏠": "1日", "㏡": "2日", "㏢": "3日",
+	"㏣": "4日", "㏤": "5日", "㏥": "6日", "㏦": "7日", "㏧": "8日", "㏨": "9日",
+	"㏩": "10日", "㏪": "11日", "㏫": "12日", "㏬": "13日", "㏭": "14日", "㏮": "15日",
+	"㏯": "16日", "㏰": "17日", "㏱": "18日", "㏲": "19日", "㏳": "20日", "㏴": "21日",
+	"㏵": "22日", "㏶": "23日", "㏷": "24日", "㏸": "25日", "㏹": "26日", "㏺": "27日",
+	"㏻": "28日", "㏼": "29日", "㏽": "30日", "㏾": "31日", "㏿": "gal", "🈀": "ほか",
+	"🈁": "ココ", "🈂": "サ",
+}
+
+func lowerQ(c *character) {
+	if c.filters&FLT_LOWER_Q != FLT_LOWER_Q {
+		return
+	}
+	v, ok := squared[string(c.val)]
+	if !ok {
+		return
+	}
+	c.cval = []byte(v)
+	c.refeed = true
+}
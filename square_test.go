@@ -0,0 +1,28 @@
+package kanaco
+
+import "testing"
+
+func TestSquared(t *testing.T) {
+	tests := []struct {
+		in, mode, expect string
+	}{
+		{"10㌔", "q", "10キロ"},
+		{"10㌔", "qk", "10ｷﾛ"},
+		{"㌢㍉㌫", "q", "センチミリパーセント"},
+		{"㍻31年", "q", "平成31年"},
+		{"㋿元年", "q", "令和元年"},
+		{"㍿カナコ", "qk", "株式会社ｶﾅｺ"},
+		{"5㎏", "q", "5kg"},
+		{"5㎏", "qA", "５ｋｇ"},
+		{"㎞㎡", "q", "kmm2"},
+		{"🈀", "q", "ほか"},
+		{"㌔⑫", "eq", "キロ12"},
+		{"㌔", "k", "㌔"},
+	}
+	for _, tt := range tests {
+		result := String(tt.in, tt.mode)
+		if result != tt.expect {
+			t.Errorf("[%s] %s: expect %s, result %s", tt.mode, tt.in, tt.expect, result)
+		}
+	}
+}
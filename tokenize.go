@@ -0,0 +1,87 @@
+package kanaco
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	Token struct {
+		Text       string // converted text
+		Start, End int    // byte offsets in the original text
+	}
+	// Tokenizer splits text converted with a mode into character n-grams for
+	// search indexing. Spaces and punctuation separate runs of characters,
+	// and a run shorter than N is a single token. The zero value tokenizes
+	// the text without converting it.
+	Tokenizer struct {
+		N     int  // length of the n-grams; bigrams when 0
+		Words bool // emit runs of ASCII letters and digits as single tokens
+		cv    *Converter
+	}
+)
+
+func NewTokenizer(mode string, opts ...Option) *Tokenizer {
+	return &Tokenizer{N: 2, cv: NewConverter(mode, opts...)}
+}
+
+func (t *Tokenizer) Tokenize(s string) []Token {
+	cv := t.cv
+	if cv == nil {
+		cv = NewConverter("")
+	}
+	b, m := cv.ConvertWithMap([]byte(s))
+	conv := string(b)
+	n := t.N
+	if n <= 0 {
+		n = 2
+	}
+	tokens := []Token{}
+	run := []int{} // offsets of the characters of the current run in conv
+	flush := func(end int) {
+		if len(run) == 0 {
+			return
+		}
+		run = append(run, end)
+		chars := len(run) - 1
+		for i := 0; i+n <= chars || (i == 0 && chars < n); i++ {
+			j := i + n
+			if j > chars {
+				j = chars
+			}
+			tokens = append(tokens, Token{
+				Text:  conv[run[i]:run[j]],
+				Start: m.ToOriginal(run[i]),
+				End:   m.ToOriginal(run[j]),
+			})
+		}
+		run = run[:0]
+	}
+	word := -1
+	for i := 0; i < len(conv); {
+		_, size := foldRune(conv[i:], 0) // ｶﾞ is a single character
+		r, _ := utf8.DecodeRuneInString(conv[i:])
+		isWord := t.Words && r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+		if word >= 0 && !isWord {
+			tokens = append(tokens, Token{Text: conv[word:i], Start: m.ToOriginal(word), End: m.ToOriginal(i)})
+			word = -1
+		}
+		switch {
+		case isWord:
+			flush(i)
+			if word < 0 {
+				word = i
+			}
+		case unicode.IsSpace(r) || unicode.IsPunct(r):
+			flush(i)
+		default:
+			run = append(run, i)
+		}
+		i += size
+	}
+	if word >= 0 {
+		tokens = append(tokens, Token{Text: conv[word:], Start: m.ToOriginal(word), End: m.ToOriginal(len(conv))})
+	}
+	flush(len(conv))
+	return tokens
+}
@@ -0,0 +1,47 @@
+package kanaco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	in := "ｶﾞｽ代 ＡＢＣ123ｶﾞｽ"
+	tokens := NewTokenizer("Ka").Tokenize(in)
+	expect := []Token{
+		{"ガス", 0, 9}, {"ス代", 6, 12},
+		{"AB", 13, 19}, {"BC", 16, 22}, {"C1", 19, 23}, {"12", 22, 24}, {"23", 23, 25}, {"3ガ", 24, 31}, {"ガス", 25, 34},
+	}
+	if !reflect.DeepEqual(tokens, expect) {
+		t.Errorf("expect %v, result %v", expect, tokens)
+	}
+	for _, tk := range tokens {
+		if NewConverter("Ka").String(in[tk.Start:tk.End]) != tk.Text {
+			t.Errorf("%v: original %s", tk, in[tk.Start:tk.End])
+		}
+	}
+
+	tz := NewTokenizer("Ka")
+	tz.Words = true
+	tokens = tz.Tokenize(in)
+	expect = []Token{
+		{"ガス", 0, 9}, {"ス代", 6, 12}, {"ABC123", 13, 25}, {"ガス", 25, 34},
+	}
+	if !reflect.DeepEqual(tokens, expect) {
+		t.Errorf("expect %v, result %v", expect, tokens)
+	}
+
+	tz = NewTokenizer("")
+	tz.N = 3
+	tokens = tz.Tokenize("ｶﾞｽ、本")
+	expect = []Token{{"ｶﾞｽ", 0, 9}, {"本", 12, 15}}
+	if !reflect.DeepEqual(tokens, expect) {
+		t.Errorf("expect %v, result %v", expect, tokens)
+	}
+
+	tokens = (&Tokenizer{}).Tokenize("ｶﾞｽ代")
+	expect = []Token{{"ｶﾞｽ", 0, 9}, {"ｽ代", 6, 12}}
+	if !reflect.DeepEqual(tokens, expect) {
+		t.Errorf("expect %v, result %v", expect, tokens)
+	}
+}
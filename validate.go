@@ -0,0 +1,123 @@
+package kanaco
+
+import (
+	"strings"
+)
+
+type (
+	// Validator checks that a string consists only of the allowed classes of
+	// characters.
+	Validator struct {
+		allow Class
+		mode  string
+		cv    *Converter
+	}
+	// Violation is a character that is not allowed.
+	Violation struct {
+		Text       string
+		Class      Class
+		Start, End int // byte offsets
+	}
+)
+
+// Allow returns a Validator that allows the given classes, e.g.
+// Allow(ClassKatakana|ClassKanaPunct|ClassFullwidthSpace) for furigana.
+func Allow(classes Class) *Validator {
+	v := &Validator{allow: classes, mode: fixMode(classes)}
+	if v.mode != "" {
+		v.cv = NewConverter(v.mode, fixExcept(classes, v.mode)...)
+	}
+	return v
+}
+
+// fixExcept returns the options that keep the kana punctuation the kana
+// modes of mode would convert, unless only the converted punctuation is
+// allowed. The k mode turns カード。 into ｶｰﾄﾞ｡, which is not what a form
+// allowing zenkaku punctuation wants.
+func fixExcept(allow Class, mode string) []Option {
+	opts := []Option{}
+	if strings.ContainsAny(mode, "kh") && (allow&ClassKanaPunct != 0 || allow&ClassHalfwidthKanaPunct == 0) {
+		opts = append(opts, Except("、。・ー゛゜"))
+	}
+	if strings.ContainsAny(mode, "KH") && (allow&ClassHalfwidthKanaPunct != 0 || allow&ClassKanaPunct == 0) {
+		opts = append(opts, Except("､｡｢｣･ｰﾞﾟ"))
+	}
+	return opts
+}
+
+// fixMode returns the mode that converts characters into the allowed classes
+// where an equivalent exists, preferring zenkaku kana.
+func fixMode(allow Class) string {
+	has := func(c Class) bool {
+		return allow&c == c
+	}
+	mode := strings.Builder{}
+	rules := []struct {
+		to, from Class
+		mode     byte
+	}{
+		{ClassKatakana, ClassHalfwidthKatakana, 'K'},
+		{ClassHiragana, ClassHalfwidthKatakana, 'H'},
+		{ClassHiragana, ClassKatakana, 'c'},
+		{ClassHalfwidthKatakana, ClassKatakana, 'k'},
+		{ClassKatakana, ClassHiragana, 'C'},
+		{ClassHalfwidthKatakana, ClassHiragana, 'h'},
+		{ClassASCIILetter, ClassFullwidthLetter, 'r'},
+		{ClassASCIIDigit, ClassFullwidthDigit, 'n'},
+		{ClassASCIIAlnum | ClassASCIISymbol, ClassFullwidthSymbol, 'a'},
+		{ClassFullwidthLetter, ClassASCIILetter, 'R'},
+		{ClassFullwidthDigit, ClassASCIIDigit, 'N'},
+		{ClassFullwidthASCII, ClassASCIISymbol, 'A'},
+		{ClassASCIISpace, ClassFullwidthSpace, 's'},
+		{ClassFullwidthSpace, ClassASCIISpace, 'S'},
+	}
+	done := Class(0)
+	for _, r := range rules {
+		if done&r.from == 0 && has(r.to) && !has(r.from) {
+			mode.WriteByte(r.mode)
+			done |= r.from
+		}
+	}
+	return mode.String()
+}
+
+// Mode returns the mode Fix applies.
+func (v *Validator) Mode() string {
+	return v.mode
+}
+
+// Valid reports whether s consists only of the allowed classes.
+func (v *Validator) Valid(s string) bool {
+	for i := 0; i < len(s); {
+		cls, n := classify(s[i:])
+		if cls&v.allow == 0 {
+			return false
+		}
+		i += n
+	}
+	return true
+}
+
+// Validate returns all characters of s that are not allowed.
+func (v *Validator) Validate(s string) []Violation {
+	violations := []Violation{}
+	for i := 0; i < len(s); {
+		cls, n := classify(s[i:])
+		if cls&v.allow == 0 {
+			violations = append(violations, Violation{Text: s[i : i+n], Class: cls, Start: i, End: i + n})
+		}
+		i += n
+	}
+	return violations
+}
+
+// Fix converts s with the mode that makes it valid, e.g. "C" for hiragana
+// when only katakana is allowed, and returns the result with the characters
+// that remain not allowed. Kana punctuation (。 ｡) is converted only when
+// its zenkaku or hankaku form is allowed and the other is not.
+func (v *Validator) Fix(s string) (string, []Violation) {
+	if v.cv != nil {
+		s = v.cv.String(s)
+	}
+	return s, v.Validate(s)
+}
@@ -0,0 +1,67 @@
+package kanaco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	data := []struct {
+		allow Class
+		mode  string
+	}{
+		{ClassKatakana | ClassKanaPunct | ClassFullwidthSpace, "KCS"},
+		{ClassHiragana | ClassASCIISpace, "Hcs"},
+		{ClassHalfwidthKatakana, "kh"},
+		{ClassHiragana | ClassHalfwidthKatakana, "c"},
+		{ClassASCIIAlnum, "rn"},
+		{ClassASCII, "rnas"},
+		{ClassFullwidthAlnum, "RN"},
+		{ClassKanji, ""},
+	}
+	for _, v := range data {
+		if result := Allow(v.allow).Mode(); result != v.mode {
+			t.Errorf("[%s] expect %s, result %s", v.allow, v.mode, result)
+		}
+	}
+
+	v := Allow(ClassKatakana | ClassKanaPunct | ClassFullwidthSpace)
+	in := "ヤマダ　はﾅｺ 1"
+	if v.Valid(in) || !v.Valid("ヤマダ　ハナコー") {
+		t.Errorf("Valid")
+	}
+	expect := []Violation{
+		{"は", ClassHiragana, 12, 15},
+		{"ﾅ", ClassHalfwidthKatakana, 15, 18},
+		{"ｺ", ClassHalfwidthKatakana, 18, 21},
+		{" ", ClassASCIISpace, 21, 22},
+		{"1", ClassASCIIDigit, 22, 23},
+	}
+	if result := v.Validate(in); !reflect.DeepEqual(result, expect) {
+		t.Errorf("expect %v, result %v", expect, result)
+	}
+	out, violations := v.Fix(in)
+	expect = []Violation{{"1", ClassASCIIDigit, 24, 25}}
+	if out != "ヤマダ　ハナコ　1" || !reflect.DeepEqual(violations, expect) {
+		t.Errorf("expect %s %v, result %s %v", "ヤマダ　ハナコ　1", expect, out, violations)
+	}
+	fixes := []struct {
+		allow      Class
+		in, expect string
+	}{
+		{ClassHalfwidthKatakana | ClassKanaPunct, "「カード」。", "「ｶーﾄﾞ」。"},
+		{ClassHalfwidthKatakana | ClassHalfwidthKanaPunct, "「カード」。", "「ｶｰﾄﾞ」｡"},
+		{ClassHalfwidthKatakana, "「カード」。", "「ｶーﾄﾞ」。"},
+		{ClassKatakana | ClassKanaPunct, "｢ｶｰﾄﾞ｣｡", "「カード」。"},
+		{ClassKatakana | ClassKanaPunct | ClassHalfwidthKanaPunct, "｢ｶｰﾄﾞ｣｡", "｢カｰド｣｡"},
+		{ClassHiragana | ClassHalfwidthKanaPunct, "｢ｶｰﾄﾞ｣", "｢かｰど｣"},
+	}
+	for _, f := range fixes {
+		if out, _ := Allow(f.allow).Fix(f.in); out != f.expect {
+			t.Errorf("[%s] expect %s, result %s", f.in, f.expect, out)
+		}
+	}
+	if violations := Allow(ClassHalfwidthKatakana).Validate("ｶﾞｱﾞ"); len(violations) != 1 || violations[0].Text != "ﾞ" {
+		t.Errorf("expect [ﾞ], result %v", violations)
+	}
+}
@@ -0,0 +1,75 @@
+package kanaco
+
+import (
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	data := []struct {
+		s      string
+		narrow int
+		wide   int
+	}{
+		{"abc", 3, 3},
+		{"カナコ", 6, 6},
+		{"ｶﾅｺ", 3, 3},
+		{"ｶﾞｽ", 3, 3},
+		{"ＡＢ　", 6, 6},
+		{"が", 2, 2},
+		{"①±", 2, 4},
+		{"\t𛀂👍‍", 4, 4},
+		{"", 0, 0},
+	}
+	for _, v := range data {
+		if result := Width(v.s); result != v.narrow {
+			t.Errorf("[%s] expect %d, result %d", v.s, v.narrow, result)
+		}
+		if result := NewDisplay(2).Width(v.s); result != v.wide {
+			t.Errorf("[%s] ambiguous: expect %d, result %d", v.s, v.wide, result)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	data := []struct {
+		s      string
+		width  int
+		tail   string
+		expect string
+	}{
+		{"ｶﾞｽﾃｰﾌﾞﾙ", 8, "", "ｶﾞｽﾃｰﾌﾞﾙ"},
+		{"ｶﾞｽﾃｰﾌﾞﾙ", 7, "", "ｶﾞｽﾃｰﾌﾞ"},
+		{"ｶﾞｽﾃｰﾌﾞﾙ", 6, "", "ｶﾞｽﾃｰ"},
+		{"ｶﾞｽﾃｰﾌﾞﾙ", 1, "", ""},
+		{"ガステーブル", 7, "…", "ガステ…"},
+		{"ガステーブル", 6, "...", "ガ..."},
+		{"がす", 2, "", "が"},
+		{"abc", 2, "...", "ab"},
+	}
+	for _, v := range data {
+		if result := Truncate(v.s, v.width, v.tail); result != v.expect {
+			t.Errorf("[%s] %d: expect %s, result %s", v.s, v.width, v.expect, result)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	data := []struct {
+		s     string
+		width int
+		left  string
+		right string
+	}{
+		{"ｶﾞｽ", 5, "  ｶﾞｽ", "ｶﾞｽ  "},
+		{"ガス", 5, " ガス", "ガス "},
+		{"ガス", 3, "ガス", "ガス"},
+	}
+	for _, v := range data {
+		if result := PadLeft(v.s, v.width); result != v.left {
+			t.Errorf("[%s] %d: expect [%s], result [%s]", v.s, v.width, v.left, result)
+		}
+		if result := PadRight(v.s, v.width); result != v.right {
+			t.Errorf("[%s] %d: expect [%s], result [%s]", v.s, v.width, v.right, result)
+		}
+	}
+}